@@ -1,6 +1,8 @@
 package binding
 
 import (
+	"errors"
+	"fmt"
 	"mime"
 	"net/http"
 	"strings"
@@ -13,15 +15,21 @@ const (
 	MIMEMultipartForm = "multipart/form-data"               // MIMEMultipartForm represents multipart form data (typically used for file uploads)
 	MIMEPOSTForm      = "application/x-www-form-urlencoded" // MIMEPOSTForm represents URL-encoded form data
 	XMLMIME           = "application/xml"                   // XMLMIME represents XML content type
+	MIMENDJSON        = "application/x-ndjson"              // MIMENDJSON represents newline-delimited JSON content type
 )
 
+// ErrUnsupportedMediaType is returned by the Strict binder for request bodies whose
+// Content-Type no binder understands.
+var ErrUnsupportedMediaType = errors.New("binding: unsupported media type")
+
 // Common binders for common MIME types
 // These pre-initialized binder instances are used to avoid creating new binders for each request.
 var (
-	jsonBinder  = JSONBinder{}  // jsonBinder handles binding of JSON request bodies
-	xmlBinder   = XMLBinder{}   // xmlBinder handles binding of XML request bodies
-	formBinder  = FormBinder{}  // formBinder handles binding of form data (both multipart and URL-encoded)
-	queryBinder = QueryBinder{} // queryBinder handles binding of URL query parameters
+	jsonBinder   = JSONBinder{}   // jsonBinder handles binding of JSON request bodies
+	xmlBinder    = XMLBinder{}    // xmlBinder handles binding of XML request bodies
+	formBinder   = FormBinder{}   // formBinder handles binding of form data (both multipart and URL-encoded)
+	queryBinder  = QueryBinder{}  // queryBinder handles binding of URL query parameters
+	ndjsonBinder = NDJSONBinder{} // ndjsonBinder handles binding of newline-delimited JSON request bodies
 )
 
 type Binder interface {
@@ -32,6 +40,7 @@ type Binder interface {
 // Content-Type parsing follows RFC 7231, section 3.1.1.1 and RFC 2045.
 // Examples of valid Content-Type values:
 //   - application/json
+//   - application/x-ndjson
 //   - application/x-www-form-urlencoded
 //   - multipart/form-data; boundary=something
 //
@@ -42,7 +51,14 @@ func Default(method, contentType string) Binder {
 	if method == http.MethodGet {
 		return QueryBinder{}
 	}
+	return ForContentType(contentType)
+}
 
+// ForContentType returns the appropriate binder based on the Content-Type header alone,
+// regardless of the HTTP method. It can be used to force body binding for requests such as
+// GET with a JSON body, which Default deliberately binds from the query string.
+// If the Content-Type header is invalid or not provided, it defaults to QueryBinder.
+func ForContentType(contentType string) Binder {
 	// Parse media type according to RFC 7231
 	mediaType, _, err := mime.ParseMediaType(contentType)
 	if err != nil {
@@ -56,9 +72,37 @@ func Default(method, contentType string) Binder {
 		return jsonBinder
 	case XMLMIME:
 		return xmlBinder
+	case MIMENDJSON:
+		return ndjsonBinder
 	case MIMEMultipartForm, MIMEPOSTForm:
 		return formBinder // Both form types use the same binder
 	default:
 		return queryBinder
 	}
 }
+
+// Strict returns a Binder that binds like Default, except that requests other than GET carrying
+// a body whose Content-Type no binder understands fail with an error wrapping ErrUnsupportedMediaType,
+// instead of having the body ignored and the query string bound. It is opted into with hx.UseBinder.
+func Strict() Binder {
+	return strictBinder{}
+}
+
+// strictBinder is the Binder returned by Strict.
+type strictBinder struct{}
+
+func (strictBinder) Bind(r *http.Request, a any) error {
+	contentType := r.Header.Get("Content-Type")
+	if r.Method != http.MethodGet && r.Body != nil && r.Body != http.NoBody && r.ContentLength != 0 {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		switch strings.ToLower(mediaType) {
+		case MIMEJSON, XMLMIME, MIMENDJSON, MIMEMultipartForm, MIMEPOSTForm:
+		default:
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrUnsupportedMediaType, err)
+			}
+			return fmt.Errorf("%w: %s", ErrUnsupportedMediaType, mediaType)
+		}
+	}
+	return Default(r.Method, contentType).Bind(r, a)
+}
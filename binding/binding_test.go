@@ -1,12 +1,20 @@
 package binding
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/eatmoreapple/hx/httpx"
+	"github.com/eatmoreapple/hx/internal/serializer"
 )
 
 type TestExtractor string
@@ -28,6 +36,7 @@ func TestDefault(t *testing.T) {
 		{http.MethodGet, "application/json", queryBinder},
 		{http.MethodPost, "application/json", jsonBinder},
 		{http.MethodPost, "application/xml", xmlBinder},
+		{http.MethodPost, "application/x-ndjson", ndjsonBinder},
 		{http.MethodPost, "application/x-www-form-urlencoded", formBinder},
 		{http.MethodPost, "multipart/form-data", formBinder},
 		{http.MethodPost, "text/plain", queryBinder},
@@ -96,3 +105,707 @@ func TestJSONBinder(t *testing.T) {
 		t.Errorf("expected name %s, got %s", "hello", data.Name)
 	}
 }
+
+func TestFormBinderMultipartValuesAndFiles(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	if err := mw.WriteField("name", "gopher"); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := mw.CreateFormFile("avatar", "avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fw.Write([]byte("png-bytes")); err != nil {
+		t.Fatal(err)
+	}
+	if err = mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	type Data struct {
+		Name   string                `form:"name"`
+		Avatar *multipart.FileHeader `form:"avatar"`
+	}
+	var data Data
+
+	if err = formBinder.Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.Name != "gopher" {
+		t.Errorf("expected name %s, got %s", "gopher", data.Name)
+	}
+	if data.Avatar == nil {
+		t.Fatal("expected avatar to be bound")
+	}
+	if data.Avatar.Filename != "avatar.png" {
+		t.Errorf("expected filename %s, got %s", "avatar.png", data.Avatar.Filename)
+	}
+}
+
+func TestGenericBinderNested(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?test=hello", nil)
+
+	type Inner struct {
+		Name httpx.FromQuery[TestExtractor]
+	}
+	type Middle struct {
+		Inner
+	}
+	type Outer struct {
+		Middle Middle
+	}
+	var o Outer
+
+	if err := Generic().Bind(req, &o); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if o.Middle.Name.String() != "hello" {
+		t.Errorf("expected name %s, got %s", "hello", o.Middle.Name.String())
+	}
+}
+
+type cyclicStruct struct {
+	Name httpx.FromQuery[TestExtractor]
+	Next *cyclicStruct
+}
+
+func TestGenericBinderCycle(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?test=hello", nil)
+
+	c := &cyclicStruct{}
+	c.Next = c
+
+	if err := Generic().Bind(req, c); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if c.Name.String() != "hello" {
+		t.Errorf("expected name %s, got %s", "hello", c.Name.String())
+	}
+}
+
+func TestBindUnexportedFields(t *testing.T) {
+	type Data struct {
+		Name   string                          `form:"name"`
+		secret string                          `form:"secret"`
+		Query  httpx.FromQuery[TestExtractor]  `form:"-"`
+		hidden httpx.FromQuery[TestExtractor]  `form:"-"`
+		avatar *multipart.FileHeader           `form:"avatar"`
+		ptr    *httpx.FromQuery[TestExtractor] `form:"-"`
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("name", "gopher")
+	_ = mw.WriteField("secret", "s3cr3t")
+	fw, err := mw.CreateFormFile("avatar", "avatar.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = fw.Write([]byte("png-bytes"))
+	_ = mw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/?test=hello", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+
+	var data Data
+	if err = formBinder.Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err = Generic().Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.Name != "gopher" {
+		t.Errorf("expected name %s, got %s", "gopher", data.Name)
+	}
+	if data.Query.String() != "hello" {
+		t.Errorf("expected query %s, got %s", "hello", data.Query.String())
+	}
+	if data.secret != "" || data.hidden.String() != "" || data.avatar != nil || data.ptr != nil {
+		t.Error("expected unexported fields to be left untouched")
+	}
+}
+
+func TestJSONBinderUseNumber(t *testing.T) {
+	body := `{"id": 1234567890123456789, "raw": 1234567890123456789}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	type Data struct {
+		ID  json.Number `json:"id"`
+		Raw any         `json:"raw"`
+	}
+	var data Data
+
+	if err := (JSONBinder{UseNumber: true}).Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if data.ID.String() != "1234567890123456789" {
+		t.Errorf("expected id %s, got %s", "1234567890123456789", data.ID.String())
+	}
+	if raw, ok := data.Raw.(json.Number); !ok || raw.String() != "1234567890123456789" {
+		t.Errorf("expected raw json.Number %s, got %#v", "1234567890123456789", data.Raw)
+	}
+}
+
+// failingSerializer is a serializer.Serializer that refuses to decode anything.
+type failingSerializer struct{}
+
+func (failingSerializer) Serialize(any, io.Writer) error { return errors.New("serialize") }
+
+func (failingSerializer) Deserialize(io.Reader, any) error { return errors.New("deserialize") }
+
+func TestJSONBinderUseNumberBypassesSerializer(t *testing.T) {
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"raw": 1}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req.WithContext(serializer.WithJSONSerializer(req.Context(), failingSerializer{}))
+	}
+
+	var data struct {
+		Raw any `json:"raw"`
+	}
+	if err := (JSONBinder{}).Bind(newRequest(), &data); err == nil {
+		t.Fatal("expected the serializer in context to be used")
+	}
+	if err := (JSONBinder{UseNumber: true}).Bind(newRequest(), &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if raw, ok := data.Raw.(json.Number); !ok || raw.String() != "1" {
+		t.Errorf("expected raw json.Number 1, got %#v", data.Raw)
+	}
+}
+
+func TestSetMaxFields(t *testing.T) {
+	defer SetMaxFields(DefaultMaxFields)
+
+	values := make(url.Values, DefaultMaxFields+1)
+	for i := range DefaultMaxFields + 1 {
+		values.Set(strconv.Itoa(i), "v")
+	}
+
+	type Data struct {
+		Zero string `form:"0"`
+	}
+
+	var data Data
+	if err := mapTo(values, &data); !errors.Is(err, ErrTooManyFields) {
+		t.Fatalf("expected ErrTooManyFields, got %v", err)
+	}
+
+	SetMaxFields(DefaultMaxFields * 2)
+	if err := mapTo(values, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Zero != "v" {
+		t.Errorf("expected value %s, got %s", "v", data.Zero)
+	}
+}
+
+func TestSetMaxFieldsOutOfRange(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic but got nil")
+		}
+	}()
+	SetMaxFields(MaxFieldsLimit + 1)
+}
+
+func TestBindingErrorMessage(t *testing.T) {
+	type Data struct {
+		Age  int   `form:"age"`
+		Tags []int `form:"tags"`
+	}
+
+	var data Data
+	err := mapTo(url.Values{"age": {"abc"}}, &data)
+	if err == nil || err.Error() != `binding field "Age": parsing int "abc": invalid syntax` {
+		t.Errorf("unexpected error message: %v", err)
+	}
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("expected error to wrap strconv.ErrSyntax, got %v", err)
+	}
+
+	err = mapTo(url.Values{"tags": {"1", "x"}}, &data)
+	if err == nil || err.Error() != `binding field "Tags": binding slice element 1: parsing int "x": invalid syntax` {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestSetValueRedactor(t *testing.T) {
+	SetValueRedactor(func(field, value string) string {
+		if field == "Pin" {
+			return "***"
+		}
+		return value
+	})
+	defer SetValueRedactor(nil)
+
+	type Data struct {
+		Pin int `form:"pin"`
+	}
+
+	var data Data
+	err := mapTo(url.Values{"pin": {"12a4"}}, &data)
+	if err == nil || err.Error() != `binding field "Pin": parsing int "***": invalid syntax` {
+		t.Errorf("unexpected error message: %v", err)
+	}
+}
+
+func TestNDJSONBinder(t *testing.T) {
+	body := "{\"name\":\"a\"}\n{\"name\":\"b\"}\n"
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-ndjson")
+
+	type Data struct {
+		Name string `json:"name"`
+	}
+	var data []Data
+
+	if err := ndjsonBinder.Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(data) != 2 || data[0].Name != "a" || data[1].Name != "b" {
+		t.Errorf("unexpected records: %+v", data)
+	}
+}
+
+// requiredValidator is a minimal SchemaValidator requiring top-level properties.
+type requiredValidator []string
+
+func (v requiredValidator) Validate(document []byte) ([]SchemaViolation, error) {
+	var object map[string]any
+	if err := json.Unmarshal(document, &object); err != nil {
+		return nil, err
+	}
+	var violations []SchemaViolation
+	for _, name := range v {
+		if _, ok := object[name]; !ok {
+			violations = append(violations, SchemaViolation{Path: "/" + name, Message: "property is required"})
+		}
+	}
+	return violations, nil
+}
+
+func TestSchemaBinder(t *testing.T) {
+	type Data struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	binder := SchemaBinder{Validator: requiredValidator{"name", "age"}}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age": 3}`))
+	var data Data
+	err := binder.Bind(req, &data)
+
+	var schemaErr *SchemaError
+	if !errors.As(err, &schemaErr) {
+		t.Fatalf("expected *SchemaError, got %v", err)
+	}
+	if len(schemaErr.Violations) != 1 || schemaErr.Violations[0].Path != "/name" {
+		t.Errorf("unexpected violations: %+v", schemaErr.Violations)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "gopher", "age": 3}`))
+	if err = binder.Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Name != "gopher" || data.Age != 3 {
+		t.Errorf("unexpected data: %+v", data)
+	}
+
+	binder.MaxBodySize = 8
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "gopher", "age": 3}`))
+	var maxBytesErr *http.MaxBytesError
+	if err = binder.Bind(req, &data); !errors.As(err, &maxBytesErr) {
+		t.Errorf("expected *http.MaxBytesError, got %v", err)
+	}
+}
+
+func TestBindArray(t *testing.T) {
+	type Data struct {
+		Point [2]int `form:"p"`
+	}
+
+	tests := []struct {
+		name     string
+		values   []string
+		expected [2]int
+		err      error
+	}{
+		{"exact", []string{"1", "2"}, [2]int{1, 2}, nil},
+		{"fewer", []string{"7"}, [2]int{7, 0}, nil},
+		{"more", []string{"1", "2", "3"}, [2]int{}, ErrArrayOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data Data
+			err := mapTo(url.Values{"p": tt.values}, &data)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected error %v, got %v", tt.err, err)
+			}
+			if data.Point != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, data.Point)
+			}
+		})
+	}
+
+	SetTruncateArrays(true)
+	defer SetTruncateArrays(false)
+
+	var data Data
+	if err := mapTo(url.Values{"p": {"1", "2", "3"}}, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Point != [2]int{1, 2} {
+		t.Errorf("expected %v, got %v", [2]int{1, 2}, data.Point)
+	}
+}
+
+func TestQueryStruct(t *testing.T) {
+	type Filter struct {
+		Sort  string `form:"sort"`
+		Order string `form:"order"`
+		Limit int    `form:"limit"`
+	}
+	type Request struct {
+		Filter QueryStruct[Filter]
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?sort=name&order=desc&limit=10", nil)
+
+	var data Request
+	if err := Generic().Bind(req, &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := Filter{Sort: "name", Order: "desc", Limit: 10}
+	if data.Filter.Value() != expected {
+		t.Errorf("expected filter %+v, got %+v", expected, data.Filter.Value())
+	}
+}
+
+func TestOptional(t *testing.T) {
+	type Data struct {
+		Name  Optional[string] `json:"name" form:"name"`
+		Age   Optional[int]    `json:"age" form:"age"`
+		Email Optional[string] `json:"email" form:"email"`
+	}
+
+	check := func(t *testing.T, data Data) {
+		t.Helper()
+		if !data.Name.Present || data.Name.Value != "" {
+			t.Errorf("expected name present and empty, got %+v", data.Name)
+		}
+		if !data.Age.Present || data.Age.Value != 3 {
+			t.Errorf("expected age present with value 3, got %+v", data.Age)
+		}
+		if data.Email.Present {
+			t.Errorf("expected email absent, got %+v", data.Email)
+		}
+	}
+
+	t.Run("query", func(t *testing.T) {
+		var data Data
+		if err := mapTo(url.Values{"name": {""}, "age": {"3"}}, &data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		check(t, data)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name": "", "age": 3}`))
+		var data Data
+		if err := jsonBinder.Bind(req, &data); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		check(t, data)
+	})
+}
+
+func TestBindValues(t *testing.T) {
+	type Event struct {
+		Type   string `form:"type"`
+		Amount int    `form:"amount"`
+		Tags   []string
+	}
+
+	values := url.Values{
+		"type":   {"charge"},
+		"amount": {"42"},
+		"Tags":   {"a", "b"},
+	}
+
+	var event Event
+	if err := BindValues(values, &event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if event.Type != "charge" || event.Amount != 42 || len(event.Tags) != 2 {
+		t.Errorf("unexpected result: %+v", event)
+	}
+
+	if err := BindValues(url.Values{"amount": {"x"}}, &event); err == nil {
+		t.Error("expected conversion error")
+	}
+
+	if err := BindValues(values, event); !errors.Is(err, ErrPointerRequired) {
+		t.Errorf("expected ErrPointerRequired, got %v", err)
+	}
+}
+
+func TestQueryBinderRawValues(t *testing.T) {
+	type Request struct {
+		Page int `form:"page"`
+		Raw  url.Values
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?page=2&sort=name&tag=a&tag=b", nil)
+
+	var dest Request
+	if err := (QueryBinder{}).Bind(req, &dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dest.Page != 2 {
+		t.Errorf("expected page 2, got %d", dest.Page)
+	}
+
+	if dest.Raw.Get("sort") != "name" || len(dest.Raw["tag"]) != 2 || dest.Raw.Get("page") != "2" {
+		t.Errorf("expected the full query, got %v", dest.Raw)
+	}
+}
+
+func TestFieldError(t *testing.T) {
+	type Data struct {
+		Age  int   `form:"age"`
+		Tags []int `form:"tags"`
+	}
+
+	var data Data
+	err := mapTo(url.Values{"age": {"abc"}}, &data)
+
+	var fieldErr *FieldError
+	if !errors.As(err, &fieldErr) {
+		t.Fatalf("expected *FieldError, got %T", err)
+	}
+
+	if fieldErr.Field != "Age" || fieldErr.Tag != "age" || fieldErr.Value != "abc" {
+		t.Errorf("unexpected field error: %+v", fieldErr)
+	}
+
+	if fieldErr.Message != `parsing int "abc": invalid syntax` {
+		t.Errorf("unexpected message: %s", fieldErr.Message)
+	}
+
+	err = mapTo(url.Values{"tags": {"1", "x"}}, &data)
+	if !errors.As(err, &fieldErr) || fieldErr.Value != "x" || fieldErr.Tag != "tags" {
+		t.Errorf("expected failing slice element in field error, got %+v", fieldErr)
+	}
+
+	encoded, _ := json.Marshal(fieldErr)
+	if !strings.Contains(string(encoded), `"field":"Tags"`) {
+		t.Errorf("expected field error to marshal, got %s", encoded)
+	}
+}
+
+func TestSetKeyVariants(t *testing.T) {
+	type Person struct {
+		FirstName string
+		UserID    int `form:"uid"`
+	}
+
+	var person Person
+	if err := mapTo(url.Values{"first_name": {"ada"}}, &person); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if person.FirstName != "" {
+		t.Errorf("expected variants to be disabled by default, got %q", person.FirstName)
+	}
+
+	SetKeyVariants(true)
+	defer SetKeyVariants(false)
+
+	tests := []struct {
+		values url.Values
+		name   string
+		id     int
+	}{
+		{url.Values{"first_name": {"ada"}, "user_id": {"1"}}, "ada", 1},
+		{url.Values{"firstName": {"grace"}, "userID": {"2"}}, "grace", 2},
+		{url.Values{"FirstName": {"exact"}, "first_name": {"variant"}, "uid": {"3"}}, "exact", 3},
+	}
+
+	for _, tt := range tests {
+		var person Person
+		if err := mapTo(tt.values, &person); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if person.FirstName != tt.name || person.UserID != tt.id {
+			t.Errorf("%v: expected %s/%d, got %s/%d", tt.values, tt.name, tt.id, person.FirstName, person.UserID)
+		}
+	}
+}
+
+func TestKeyVariantNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		snake string
+		camel string
+	}{
+		{"FirstName", "first_name", "firstName"},
+		{"UserID", "user_id", "userID"},
+		{"HTTPStatus", "http_status", "httpStatus"},
+		{"ID", "id", "id"},
+	}
+
+	for _, tt := range tests {
+		if got := snakeCase(tt.name); got != tt.snake {
+			t.Errorf("snakeCase(%q) = %q, want %q", tt.name, got, tt.snake)
+		}
+		if got := camelCase(tt.name); got != tt.camel {
+			t.Errorf("camelCase(%q) = %q, want %q", tt.name, got, tt.camel)
+		}
+	}
+}
+
+type eventPayload interface{ kind() string }
+
+type signupPayload struct {
+	Email string `json:"email"`
+}
+
+func (*signupPayload) kind() string { return "signup" }
+
+type purchasePayload struct {
+	Amount int `json:"amount"`
+}
+
+func (*purchasePayload) kind() string { return "purchase" }
+
+func TestRegisterDiscriminator(t *testing.T) {
+	RegisterDiscriminator[eventPayload]("signup", func() eventPayload { return &signupPayload{} })
+	RegisterDiscriminator[eventPayload]("purchase", func() eventPayload { return &purchasePayload{} })
+
+	type Event struct {
+		ID      string       `json:"id"`
+		Payload eventPayload `json:"payload" discriminator:"type"`
+	}
+
+	bind := func(body string) (Event, error) {
+		var event Event
+		req := httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body))
+		err := JSONBinder{}.Bind(req, &event)
+		return event, err
+	}
+
+	event, err := bind(`{"id":"1","payload":{"type":"signup","email":"gopher@example.com"}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signup, ok := event.Payload.(*signupPayload); !ok || signup.Email != "gopher@example.com" || event.ID != "1" {
+		t.Errorf("expected signup payload, got %#v", event.Payload)
+	}
+
+	event, err = bind(`{"id":"2","payload":{"type":"purchase","amount":42}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purchase, ok := event.Payload.(*purchasePayload); !ok || purchase.Amount != 42 {
+		t.Errorf("expected purchase payload, got %#v", event.Payload)
+	}
+
+	if _, err := bind(`{"id":"3","payload":{"type":"refund"}}`); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Errorf("expected ErrUnknownDiscriminator, got %v", err)
+	}
+
+	RegisterDiscriminator[eventPayload]("void", func() eventPayload { return nil })
+	RegisterDiscriminator[eventPayload]("nil", func() eventPayload { return (*signupPayload)(nil) })
+	for _, kind := range []string{"void", "nil"} {
+		if _, err := bind(`{"id":"4","payload":{"type":"` + kind + `"}}`); !errors.Is(err, ErrUnknownDiscriminator) {
+			t.Errorf("%s: expected ErrUnknownDiscriminator, got %v", kind, err)
+		}
+	}
+}
+
+func TestJoinTag(t *testing.T) {
+	var dest struct {
+		Tags  string  `form:"tags" join:","`
+		Label *string `form:"label" join:" "`
+		First string  `form:"first"`
+	}
+
+	values := url.Values{
+		"tags":  {"a", "b", "c"},
+		"label": {"hello", "world"},
+		"first": {"x", "y"},
+	}
+	if err := BindValues(values, &dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if dest.Tags != "a,b,c" {
+		t.Errorf("expected joined tags %q, got %q", "a,b,c", dest.Tags)
+	}
+
+	if dest.Label == nil || *dest.Label != "hello world" {
+		t.Errorf("expected joined label %q, got %v", "hello world", dest.Label)
+	}
+
+	if dest.First != "x" {
+		t.Errorf("expected first value %q without join tag, got %q", "x", dest.First)
+	}
+}
+
+func TestIndexedStructSlice(t *testing.T) {
+	type Item struct {
+		Name string `form:"name"`
+		Qty  int    `form:"qty"`
+	}
+
+	var dest struct {
+		Items []Item  `form:"items"`
+		Refs  []*Item `form:"refs"`
+	}
+
+	values, err := url.ParseQuery("items[0].name=a&items[0].qty=2&items[1].name=b&items[1].qty=5&refs[1].name=c")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := BindValues(values, &dest); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []Item{{Name: "a", Qty: 2}, {Name: "b", Qty: 5}}
+	if len(dest.Items) != 2 || dest.Items[0] != expected[0] || dest.Items[1] != expected[1] {
+		t.Errorf("expected items %v, got %v", expected, dest.Items)
+	}
+
+	if len(dest.Refs) != 2 || dest.Refs[0] != nil || dest.Refs[1].Name != "c" {
+		t.Errorf("expected refs grown to index 1, got %v", dest.Refs)
+	}
+
+	values = url.Values{"items[0].qty": {"many"}}
+	var invalid struct {
+		Items []Item `form:"items"`
+	}
+	var fieldErr *FieldError
+	if err := BindValues(values, &invalid); !errors.As(err, &fieldErr) || fieldErr.Field != "Qty" {
+		t.Errorf("expected a FieldError for Qty, got %v", err)
+	}
+
+	values = url.Values{"items[" + strconv.Itoa(DefaultMaxFields) + "].name": {"x"}}
+	if err := BindValues(values, &invalid); !errors.Is(err, ErrTooManyFields) {
+		t.Errorf("expected ErrTooManyFields for a huge index, got %v", err)
+	}
+}
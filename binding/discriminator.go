@@ -0,0 +1,147 @@
+package binding
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownDiscriminator is wrapped by the error returned when a polymorphic JSON payload
+// carries a discriminator value without a registered factory.
+var ErrUnknownDiscriminator = errors.New("binding: unknown discriminator")
+
+// discriminatorKey identifies a factory by interface type and discriminator value.
+type discriminatorKey struct {
+	iface reflect.Type
+	value string
+}
+
+var (
+	// discriminatorFactories holds the factories registered with RegisterDiscriminator.
+	discriminatorFactories sync.Map // map[discriminatorKey]func() any
+
+	// discriminatedTypes caches whether a struct type has discriminated fields.
+	discriminatedTypes sync.Map // map[reflect.Type]bool
+)
+
+// RegisterDiscriminator registers factory as the constructor of the concrete type bound into
+// fields of interface type I when the JSON object carries value in its discriminator property.
+// The factory should return a non-nil pointer so the payload can be decoded into it;
+// binding fails with ErrUnknownDiscriminator when it returns nil.
+// Registering the same value again replaces its factory. It should be called during initialization.
+//
+// Fields opt in with the discriminator tag naming the property to look at:
+//
+//	type Event struct {
+//	    ID      string  `json:"id"`
+//	    Payload Payload `json:"payload" discriminator:"type"`
+//	}
+//
+//	binding.RegisterDiscriminator[Payload]("signup", func() Payload { return &Signup{} })
+//	binding.RegisterDiscriminator[Payload]("purchase", func() Payload { return &Purchase{} })
+//
+// A body of {"id": "1", "payload": {"type": "signup", ...}} is then decoded into a *Signup.
+func RegisterDiscriminator[I any](value string, factory func() I) {
+	key := discriminatorKey{iface: reflect.TypeFor[I](), value: value}
+	discriminatorFactories.Store(key, func() any { return factory() })
+}
+
+// hasDiscriminatedFields reports whether t is a struct with interface fields tagged with discriminator.
+func hasDiscriminatedFields(t reflect.Type) bool {
+	if cached, ok := discriminatedTypes.Load(t); ok {
+		return cached.(bool)
+	}
+	found := false
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.Type.Kind() == reflect.Interface && field.Tag.Get("discriminator") != "" {
+				found = true
+				break
+			}
+		}
+	}
+	discriminatedTypes.Store(t, found)
+	return found
+}
+
+// prepareDiscriminated reads the body and sets the discriminated fields of the struct a points to
+// to fresh values built by the registered factories, so that decoding the returned body fills them.
+// Non-struct destinations and structs without discriminated fields leave body untouched.
+func prepareDiscriminated(body io.Reader, a any) (io.Reader, error) {
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Pointer || v.IsNil() || !hasDiscriminatedFields(v.Elem().Type()) {
+		return body, nil
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		// let the decoder report the syntax error
+		return bytes.NewReader(data), nil
+	}
+
+	v = v.Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		property := field.Tag.Get("discriminator")
+		if field.Type.Kind() != reflect.Interface || property == "" ||
+			!field.IsExported() || field.Tag.Get("json") == "-" {
+			continue
+		}
+		raw, ok := jsonProperty(object, jsonFieldName(field))
+		if !ok {
+			continue
+		}
+		var payload map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			// null or not an object, leave it to the decoder
+			continue
+		}
+		var value string
+		if err := json.Unmarshal(payload[property], &value); err != nil {
+			return nil, fmt.Errorf("%w: field %s has no string %q property", ErrUnknownDiscriminator, field.Name, property)
+		}
+		factory, ok := discriminatorFactories.Load(discriminatorKey{iface: field.Type, value: value})
+		if !ok {
+			return nil, fmt.Errorf("%w: %q for field %s", ErrUnknownDiscriminator, value, field.Name)
+		}
+		instance := reflect.ValueOf(factory.(func() any)())
+		if !instance.IsValid() || !instance.Type().AssignableTo(field.Type) ||
+			(instance.Kind() == reflect.Pointer && instance.IsNil()) {
+			return nil, fmt.Errorf("%w: factory for %q returned no value for field %s", ErrUnknownDiscriminator, value, field.Name)
+		}
+		v.Field(i).Set(instance)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// jsonFieldName returns the JSON property name of a struct field.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// jsonProperty looks up name in object, falling back to a case-insensitive match like encoding/json.
+func jsonProperty(object map[string]json.RawMessage, name string) (json.RawMessage, bool) {
+	if raw, ok := object[name]; ok {
+		return raw, true
+	}
+	for key, raw := range object {
+		if strings.EqualFold(key, name) {
+			return raw, true
+		}
+	}
+	return nil, false
+}
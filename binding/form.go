@@ -53,16 +53,23 @@ func (f FormBinder) Bind(r *http.Request, dest any) error {
 		for k, v := range r.MultipartForm.Value {
 			values[k] = v
 		}
+	}
 
-		// Handle file uploads if the destination struct has multipart.FileHeader fields
-		if len(r.MultipartForm.File) > 0 {
-			if err := handleFileUploads(r.MultipartForm.File, dest); err != nil {
-				return err
-			}
-		}
+	// Bind plain values first so file fields are always populated from the parsed files
+	if err := mapToReport(values, dest, ReportFromContext(r.Context())); err != nil {
+		return err
 	}
 
-	return mapTo(values, dest)
+	// Handle file uploads if the destination struct has multipart.FileHeader fields
+	if r.MultipartForm != nil && len(r.MultipartForm.File) > 0 {
+		return handleFileUploads(r.MultipartForm.File, dest)
+	}
+	return nil
+}
+
+// isFileHeaderType reports whether t is bound from multipart files rather than form values.
+func isFileHeaderType(t reflect.Type) bool {
+	return t == fileHeaderType || t == fileHeaderSliceType
 }
 
 // handleFileUploads processes file uploads in multipart forms
@@ -78,7 +85,10 @@ func handleFileUploads(files map[string][]*multipart.FileHeader, dest any) error
 
 	for i := 0; i < v.NumField(); i++ {
 		field := v.Type().Field(i)
-		if field.Type == fileHeaderType || field.Type == fileHeaderSliceType {
+		if !v.Field(i).CanSet() { // skip unexported fields
+			continue
+		}
+		if isFileHeaderType(field.Type) {
 			tag := cmp.Or(field.Tag.Get("form"), field.Name)
 			if file, ok := files[tag]; ok {
 				if field.Type == fileHeaderType {
@@ -17,6 +17,7 @@ type GenericBinder struct{}
 // It uses reflection to inspect the struct fields and checks if they implement the
 // `httpx.RequestExtractor` interface. If a field implements the interface, the
 // `FromRequest` method is invoked to extract and set the data from the request.
+// Nested and embedded struct fields are inspected recursively.
 //
 // Parameters:
 //   - r: The HTTP request containing the data to be bound.
@@ -32,6 +33,19 @@ func (g GenericBinder) Bind(r *http.Request, a any) error {
 	if v.Kind() != reflect.Struct {
 		return nil
 	}
+	return g.bindStruct(r, v, make(map[reflect.Type]struct{}))
+}
+
+// bindStruct populates the extractor fields of v and recurses into nested struct fields.
+// The visiting set holds the struct types on the current path and guards against cycles
+// introduced by self-referencing pointer fields.
+func (g GenericBinder) bindStruct(r *http.Request, v reflect.Value, visiting map[reflect.Type]struct{}) error {
+	t := v.Type()
+	if _, ok := visiting[t]; ok {
+		return nil
+	}
+	visiting[t] = struct{}{}
+	defer delete(visiting, t)
 
 	// Iterate over each field in the struct.
 	for i := 0; i < v.NumField(); i++ {
@@ -42,10 +56,14 @@ func (g GenericBinder) Bind(r *http.Request, a any) error {
 
 		// If the field implements `httpx.RequestExtractor`, process it.
 		if isImplementedRequestExtractor {
+			// Unexported fields cannot be populated, skip them.
+			if !field.CanSet() {
+				continue
+			}
 			// If the field is a pointer and is nil, initialize it with a new instance of its type.
 			if isPointer && field.IsNil() {
 				field.Set(reflect.New(field.Type().Elem()))
-			} else {
+			} else if !isPointer {
 				// If the field is not a pointer, convert it to a pointer.
 				field = field.Addr()
 			}
@@ -54,6 +72,19 @@ func (g GenericBinder) Bind(r *http.Request, a any) error {
 			if err := extractor.FromRequest(r); err != nil {
 				return err
 			}
+			continue
+		}
+
+		// Recurse into nested structs so their extractor fields are processed too.
+		switch {
+		case field.Kind() == reflect.Struct:
+			if err := g.bindStruct(r, field, visiting); err != nil {
+				return err
+			}
+		case isPointer && !field.IsNil() && field.Elem().Kind() == reflect.Struct:
+			if err := g.bindStruct(r, field.Elem(), visiting); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
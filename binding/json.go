@@ -1,13 +1,32 @@
 package binding
 
 import (
+	"encoding/json"
 	"net/http"
 
 	"github.com/eatmoreapple/hx/internal/serializer"
 )
 
-type JSONBinder struct{}
+// JSONBinder handles binding of JSON request bodies.
+type JSONBinder struct {
+	// UseNumber decodes numbers into json.Number instead of float64,
+	// preserving precision for large integers held in any-typed fields.
+	// The body is then decoded by encoding/json rather than by the serializer set with
+	// SetJSONSerializer or WithSerializer, which have no way to switch this mode on.
+	UseNumber bool
+}
 
+// Bind decodes the body into a. Interface fields tagged with discriminator are first set
+// to the concrete types registered with RegisterDiscriminator.
 func (j JSONBinder) Bind(r *http.Request, a any) error {
-	return serializer.JSONSerializer().Deserialize(r.Body, a)
+	body, err := prepareDiscriminated(r.Body, a)
+	if err != nil {
+		return err
+	}
+	if j.UseNumber {
+		decoder := json.NewDecoder(body)
+		decoder.UseNumber()
+		return decoder.Decode(a)
+	}
+	return serializer.JSONSerializerFromContext(r.Context()).Deserialize(body, a)
 }
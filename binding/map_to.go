@@ -7,6 +7,8 @@ import (
 	"net/url"
 	"reflect"
 	"strconv"
+	"strings"
+	"unicode"
 )
 
 // Common errors that can occur during binding
@@ -15,16 +17,212 @@ var (
 	ErrStructRequired  = errors.New("binding: destination must be a struct")
 	ErrUnsupportedType = errors.New("binding: unsupported type")
 	ErrTooManyFields   = errors.New("binding: too many fields")
+	ErrArrayOverflow   = errors.New("binding: too many values for array")
+	ErrMissingField    = errors.New("binding: missing required field")
 )
 
 const (
-	maxFields = 1000 // Maximum number of fields to prevent DOS attacks
+	DefaultMaxFields = 1000   // DefaultMaxFields is the default maximum number of fields to prevent DOS attacks
+	MaxFieldsLimit   = 100000 // MaxFieldsLimit is the upper bound accepted by SetMaxFields
 )
 
+// maxFields is the maximum number of form keys or slice values accepted when binding.
+var maxFields = DefaultMaxFields
+
+// SetMaxFields sets the maximum number of form keys or slice values accepted when binding.
+// This allows large trusted forms to raise the limit above DefaultMaxFields.
+// It should be called during initialization, before any request is bound.
+// Panics if n is not in the range [1, MaxFieldsLimit].
+func SetMaxFields(n int) {
+	if n <= 0 || n > MaxFieldsLimit {
+		panic(fmt.Sprintf("binding: max fields must be between 1 and %d", MaxFieldsLimit))
+	}
+	maxFields = n
+}
+
+// truncateArrays controls whether surplus values are dropped when binding into fixed-size arrays.
+var truncateArrays = false
+
+// SetTruncateArrays controls how fixed-size array fields handle more values than their length.
+// By default binding fails with ErrArrayOverflow; when truncate is true the surplus values are ignored.
+func SetTruncateArrays(truncate bool) {
+	truncateArrays = truncate
+}
+
+// keyVariants controls whether snake_case and camelCase variants of field names are tried.
+var keyVariants = false
+
+// SetKeyVariants controls whether form and query binding falls back to naming variants of the
+// field name when the key from the tag, or the field name itself, is absent from the request.
+// When enabled, a field FirstName also binds from "first_name" and then "firstName", so clients
+// following different naming conventions are accepted. It is disabled by default, as variants
+// may collide with other keys.
+func SetKeyVariants(enabled bool) {
+	keyVariants = enabled
+}
+
+// snakeCase converts a Go field name into snake_case, keeping acronyms together: "UserID" -> "user_id".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, c := range runes {
+		if unicode.IsUpper(c) {
+			startsWord := i > 0 && (!unicode.IsUpper(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+			if startsWord {
+				sb.WriteByte('_')
+			}
+			c = unicode.ToLower(c)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// camelCase converts a Go field name into camelCase by lowering its leading word: "UserID" -> "userID".
+func camelCase(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		// stop before the last upper case letter of an acronym followed by a new word
+		if !unicode.IsUpper(runes[i]) || i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
+
+// lookupValues returns the values for key, falling back to naming variants of field
+// when enabled with SetKeyVariants. It also returns the key the values were found under.
+func lookupValues(values url.Values, key, field string) ([]string, string, bool) {
+	if value, ok := values[key]; ok || !keyVariants {
+		return value, key, ok
+	}
+	for _, variant := range []string{snakeCase(field), camelCase(field)} {
+		if value, ok := values[variant]; ok {
+			return value, variant, true
+		}
+	}
+	return nil, key, false
+}
+
+// valueRedactor rewrites raw input values before they are included in binding errors.
+// A nil valueRedactor leaves values untouched.
+var valueRedactor func(field, value string) string
+
+// SetValueRedactor sets a hook that rewrites raw input values included in binding error messages.
+// The hook receives the struct field name and the raw value and returns the text to report,
+// allowing sensitive input to be masked before errors reach logs.
+// Passing nil restores the default behavior of reporting values verbatim.
+func SetValueRedactor(redactor func(field, value string) string) {
+	valueRedactor = redactor
+}
+
+// parseError reports a raw input value that could not be converted to the field type.
+type parseError struct {
+	kind  string // Name of the target kind, e.g. "int"
+	value string // Raw input value, possibly redacted
+	err   error  // Underlying conversion error
+}
+
+func (e *parseError) Error() string {
+	return fmt.Sprintf("parsing %s %q: %v", e.kind, e.value, e.err)
+}
+
+func (e *parseError) Unwrap() error {
+	return e.err
+}
+
+// newParseError creates a parseError, unwrapping strconv.NumError to avoid repeating the input.
+func newParseError(kind, value string, err error) error {
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		err = numErr.Err
+	}
+	return &parseError{kind: kind, value: value, err: err}
+}
+
+// redact applies the value redactor to the parseError in err's chain, if any.
+func redact(field string, err error) error {
+	if valueRedactor == nil {
+		return err
+	}
+	var pe *parseError
+	if errors.As(err, &pe) {
+		pe.value = valueRedactor(field, pe.value)
+	}
+	return err
+}
+
+// FieldError describes a request value that could not be bound to a struct field.
+// Binding errors for form, query and path values are *FieldError, so they can be rendered
+// as machine-readable responses:
+//
+//	var fieldErr *binding.FieldError
+//	if errors.As(err, &fieldErr) {
+//	    return httpx.JSONResponse{Data: fieldErr, StatusCode: http.StatusBadRequest}, nil
+//	}
+type FieldError struct {
+	Field   string `json:"field"`   // Name of the struct field
+	Tag     string `json:"tag"`     // Key the value was read from, from the struct tag or the field name
+	Value   string `json:"value"`   // Raw value that failed to convert, redacted by the value redactor
+	Message string `json:"message"` // Description of the failure, e.g. `parsing int "abc": invalid syntax`
+	err     error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("binding field %q: %s", e.Field, e.Message)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.err
+}
+
+// newFieldError creates a FieldError for the field bound from values under tag.
+// The raw value is taken from the parseError in err's chain when present, as it
+// identifies the failing element of a slice, or from the first value otherwise.
+func newFieldError(field, tag string, values []string, err error) *FieldError {
+	err = redact(field, err)
+	var value string
+	var pe *parseError
+	switch {
+	case errors.As(err, &pe):
+		value = pe.value
+	case len(values) > 0:
+		value = values[0]
+		if valueRedactor != nil {
+			value = valueRedactor(field, value)
+		}
+	}
+	return &FieldError{Field: field, Tag: tag, Value: value, Message: err.Error(), err: err}
+}
+
 // mapTo maps url.Values to a struct using reflection.
 // The struct fields should be tagged with "form" tags.
 // If a field's tag is "-", it will be skipped.
+// Fields of type url.Values receive a copy of all values, e.g. the whole query string.
+// Fields tagged with binding:"required" fail with a FieldError wrapping ErrMissingField when absent.
+// Slices of structs are bound from indexed, dotted keys: items[0].name=a&items[1].name=b
+// binds the name field of two elements, and the slice grows to the highest index.
+// Non-slice fields bind the first value, unless they are tagged with join:"sep",
+// in which case every value is joined with sep: ?tags=a&tags=b binds "a,b" with join:",".
 func mapTo(values url.Values, dest any) error {
+	return mapToReport(values, dest, nil)
+}
+
+// BindValues binds already parsed url.Values into dest, which must be a pointer to a struct.
+// It applies the same "form" tags, conversions and limits as the form and query binders,
+// so values obtained outside the request flow, e.g. a verified webhook payload, can be bound:
+//
+//	values, _ := url.ParseQuery(payload)
+//	var event WebhookEvent
+//	err := binding.BindValues(values, &event)
+func BindValues(values url.Values, dest any) error {
+	return mapTo(values, dest)
+}
+
+// mapToReport is like mapTo but records per-field diagnostics into report when it is not nil.
+func mapToReport(values url.Values, dest any, report *BindReport) error {
 	if len(values) > maxFields {
 		return ErrTooManyFields
 	}
@@ -46,17 +244,141 @@ func mapTo(values url.Values, dest any) error {
 		if tag == "-" { // skip this field
 			continue
 		}
-		if value, ok := values[tag]; ok {
-			if err := setTo(v.Field(i), value); err != nil {
-				return fmt.Errorf("binding field %q: %w", f.Name, err)
+		if isFileHeaderType(f.Type) { // bound by handleFileUploads
+			continue
+		}
+		field := v.Field(i)
+		if !field.CanSet() { // skip unexported fields
+			continue
+		}
+		if f.Type == valuesType { // receives every value
+			field.Set(reflect.ValueOf(cloneValues(values)))
+			report.add(FieldReport{Field: f.Name, Key: tag, Type: f.Type.String(), Found: true})
+			continue
+		}
+		if isStructSlice(f.Type) { // bound from indexed keys such as items[0].name
+			found, err := bindIndexed(values, tag, field)
+			report.add(FieldReport{Field: f.Name, Key: tag, Type: f.Type.String(), Found: found, Err: err})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		value, tag, ok := lookupValues(values, tag, f.Name)
+		if !ok {
+			var err error
+			if f.Tag.Get("binding") == "required" {
+				err = newFieldError(f.Name, tag, nil, ErrMissingField)
 			}
+			report.add(FieldReport{Field: f.Name, Key: tag, Type: f.Type.String(), Err: err})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if sep, ok := f.Tag.Lookup("join"); ok && len(value) > 1 && !isMultiValued(f.Type) {
+			value = []string{strings.Join(value, sep)}
+		}
+		err := setTo(field, value)
+		if err != nil {
+			err = newFieldError(f.Name, tag, value, err)
+		}
+		report.add(FieldReport{Field: f.Name, Key: tag, Type: f.Type.String(), Found: true, Values: value, Err: err})
+		if err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// isStructSlice reports whether t is a slice of structs or of pointers to structs.
+func isStructSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	return elem.Kind() == reflect.Struct
+}
+
+// bindIndexed binds the keys prefix[i].name into the fields of element i of a slice of structs.
+// It reports whether any indexed key was found. Keys with malformed indexes are ignored,
+// and indexes of maxFields or more are rejected with ErrTooManyFields.
+func bindIndexed(values url.Values, prefix string, field reflect.Value) (bool, error) {
+	elements := make(map[int]url.Values)
+	length := 0
+	for key, value := range values {
+		rest, ok := strings.CutPrefix(key, prefix+"[")
+		if !ok {
+			continue
+		}
+		index, name, ok := strings.Cut(rest, "].")
+		if !ok {
+			continue
+		}
+		i, err := strconv.Atoi(index)
+		if err != nil || i < 0 {
+			continue
+		}
+		if i >= maxFields {
+			return true, ErrTooManyFields
+		}
+		if elements[i] == nil {
+			elements[i] = make(url.Values)
+		}
+		elements[i][name] = value
+		length = max(length, i+1)
+	}
+	if len(elements) == 0 {
+		return false, nil
+	}
+
+	slice := reflect.MakeSlice(field.Type(), length, length)
+	isPtr := field.Type().Elem().Kind() == reflect.Ptr
+	for i, element := range elements {
+		target := slice.Index(i).Addr()
+		if isPtr {
+			slice.Index(i).Set(reflect.New(field.Type().Elem().Elem()))
+			target = slice.Index(i)
+		}
+		if err := mapTo(element, target.Interface()); err != nil {
+			return true, fmt.Errorf("binding slice element %d: %w", i, err)
+		}
+	}
+	field.Set(slice)
+	return true, nil
+}
+
+// isMultiValued reports whether t, or the type it points to, binds every value rather than the first.
+func isMultiValued(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Slice || t.Kind() == reflect.Array
+}
+
+// valuesType is the reflect type for url.Values.
+var valuesType = reflect.TypeFor[url.Values]()
+
+// cloneValues returns a deep copy of values, so the bound field does not alias the request's map.
+func cloneValues(values url.Values) url.Values {
+	clone := make(url.Values, len(values))
+	for key, value := range values {
+		clone[key] = append([]string(nil), value...)
+	}
+	return clone
+}
+
 // setTo sets a reflect.Value from a slice of strings
 func setTo(field reflect.Value, value []string) error {
+	// Optional fields record presence and bind into their wrapped value
+	if field.CanAddr() && field.Addr().Type().Implements(optionalType) {
+		opt, _ := reflect.TypeAssert[optional](field.Addr())
+		return setTo(opt.optionalValue(), value)
+	}
+
 	if field.Kind() == reflect.Ptr {
 		if field.IsNil() {
 			field.Set(reflect.New(field.Type().Elem()))
@@ -67,6 +389,8 @@ func setTo(field reflect.Value, value []string) error {
 	switch field.Kind() {
 	case reflect.Slice:
 		return bindSlice(field, value)
+	case reflect.Array:
+		return bindArray(field, value)
 	default:
 		if len(value) == 0 {
 			field.Set(reflect.Zero(field.Type()))
@@ -93,6 +417,32 @@ func bindSlice(field reflect.Value, formValue []string) error {
 	return bindValueSlice(field, formValue)
 }
 
+// bindArray handles binding of fixed-size array types.
+// Elements without a corresponding value are set to their zero value.
+func bindArray(field reflect.Value, formValue []string) error {
+	if len(formValue) > field.Len() {
+		if !truncateArrays {
+			return fmt.Errorf("%w: got %d values for length %d", ErrArrayOverflow, len(formValue), field.Len())
+		}
+		formValue = formValue[:field.Len()]
+	}
+
+	array := reflect.New(field.Type()).Elem()
+	isPtr := field.Type().Elem().Kind() == reflect.Ptr
+	for i, v := range formValue {
+		elem := array.Index(i)
+		if isPtr {
+			elem.Set(reflect.New(elem.Type().Elem()))
+			elem = elem.Elem()
+		}
+		if err := setValue(elem, v); err != nil {
+			return fmt.Errorf("binding array element %d: %w", i, err)
+		}
+	}
+	field.Set(array)
+	return nil
+}
+
 // bindPtrSlice handles binding of slices of pointers
 func bindPtrSlice(field reflect.Value, formValue []string) error {
 	slice := reflect.MakeSlice(field.Type(), len(formValue), len(formValue))
@@ -127,7 +477,7 @@ func bindInt(field reflect.Value, formValue string, bitSize int) error {
 	}
 	v, err := strconv.ParseInt(formValue, 10, bitSize)
 	if err != nil {
-		return fmt.Errorf("parsing int: %w", err)
+		return newParseError("int", formValue, err)
 	}
 	field.SetInt(v)
 	return nil
@@ -141,7 +491,7 @@ func bindUint(field reflect.Value, formValue string, bitSize int) error {
 	}
 	v, err := strconv.ParseUint(formValue, 10, bitSize)
 	if err != nil {
-		return fmt.Errorf("parsing uint: %w", err)
+		return newParseError("uint", formValue, err)
 	}
 	field.SetUint(v)
 	return nil
@@ -155,7 +505,7 @@ func bindFloat(field reflect.Value, formValue string, bitSize int) error {
 	}
 	v, err := strconv.ParseFloat(formValue, bitSize)
 	if err != nil {
-		return fmt.Errorf("parsing float: %w", err)
+		return newParseError("float", formValue, err)
 	}
 	field.SetFloat(v)
 	return nil
@@ -169,7 +519,7 @@ func bindBool(field reflect.Value, formValue string) error {
 	}
 	v, err := strconv.ParseBool(formValue)
 	if err != nil {
-		return fmt.Errorf("parsing bool: %w", err)
+		return newParseError("bool", formValue, err)
 	}
 	field.SetBool(v)
 	return nil
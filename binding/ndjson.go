@@ -0,0 +1,37 @@
+package binding
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// NDJSONBinder handles binding of newline-delimited JSON request bodies.
+// When the destination is a pointer to a slice, every record is decoded and appended to it.
+// For any other destination the body is left untouched, so streaming extractors such as
+// httpx.NDJSON can consume the records one at a time.
+type NDJSONBinder struct{}
+
+func (n NDJSONBinder) Bind(r *http.Request, a any) error {
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		return nil
+	}
+	slice := v.Elem()
+	decoder := json.NewDecoder(r.Body)
+	for {
+		if slice.Len() >= maxFields {
+			return ErrTooManyFields
+		}
+		elem := reflect.New(slice.Type().Elem())
+		if err := decoder.Decode(elem.Interface()); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+}
@@ -0,0 +1,58 @@
+package binding
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Optional wraps a value together with whether it was present in the request.
+// It distinguishes a field that was explicitly set, even to its zero value, from one
+// that was omitted, which is what PATCH-style partial updates need.
+//
+// Optional is populated by the form and query binders when the key exists, and by
+// JSON decoding when the property exists. A JSON null marks the field present with
+// its zero value.
+//
+// Example:
+//
+//	type PatchUser struct {
+//	    Name binding.Optional[string] `json:"name" form:"name"`
+//	}
+//
+//	if req.Name.Present {
+//	    user.Name = req.Name.Value
+//	}
+type Optional[T any] struct {
+	Value   T
+	Present bool
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It is only called when the property exists.
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Present = true
+	if string(data) == "null" {
+		var zero T
+		o.Value = zero
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// MarshalJSON implements json.Marshaler by encoding the wrapped value.
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(o.Value)
+}
+
+// optionalValue lets mapTo populate Optional fields without knowing T.
+func (o *Optional[T]) optionalValue() reflect.Value {
+	o.Present = true
+	return reflect.ValueOf(&o.Value).Elem()
+}
+
+// optional is implemented by *Optional[T].
+type optional interface {
+	optionalValue() reflect.Value
+}
+
+// optionalType is the reflect type for the optional interface.
+var optionalType = reflect.TypeFor[optional]()
@@ -0,0 +1,58 @@
+package binding
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// PathBinder binds path parameters into struct fields tagged with "path".
+// The tag names the wildcard of the route pattern, so for a route "PUT /users/{id}"
+// a field tagged `path:"id"` receives the matching segment, converted like form values.
+// Untagged fields and parameters absent from the request are left untouched,
+// which lets PathBinder run after a body binder on the same struct:
+//
+//	type UpdateUser struct {
+//	    ID   int    `path:"id" json:"-"`
+//	    Name string `json:"name"`
+//	}
+type PathBinder struct{}
+
+func (p PathBinder) Bind(r *http.Request, a any) error {
+	v := reflect.ValueOf(a)
+	if v.Kind() != reflect.Ptr {
+		return ErrPointerRequired
+	}
+	v = v.Elem()
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, ok := f.Tag.Lookup("path")
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+		field := v.Field(i)
+		if !field.CanSet() { // skip unexported fields
+			continue
+		}
+		value := r.PathValue(name)
+		if value == "" {
+			continue
+		}
+		if err := setTo(field, []string{value}); err != nil {
+			return newFieldError(f.Name, name, []string{value}, err)
+		}
+	}
+	return nil
+}
+
+// pathBinder is a singleton instance of PathBinder.
+var pathBinder = &PathBinder{}
+
+// Path returns the shared PathBinder instance.
+func Path() *PathBinder {
+	return pathBinder
+}
@@ -6,5 +6,36 @@ type QueryBinder struct{}
 
 func (q QueryBinder) Bind(r *http.Request, a any) error {
 	query := r.URL.Query()
-	return mapTo(query, a)
+	return mapToReport(query, a, ReportFromContext(r.Context()))
+}
+
+// QueryStruct is a RequestExtractor that binds a whole struct of type T from the query string,
+// using the same "form" tags and conversions as QueryBinder.
+// It groups related query parameters into a nested struct of the request.
+//
+// It lives in the binding package rather than httpx/extractor because it reuses the
+// binder's mapping logic, which the extractor package cannot import.
+//
+// Example:
+//
+//	type Filter struct {
+//	    Sort  string `form:"sort"`
+//	    Order string `form:"order"`
+//	}
+//
+//	type ListRequest struct {
+//	    Filter binding.QueryStruct[Filter]
+//	}
+type QueryStruct[T any] struct {
+	value T
+}
+
+// FromRequest implements httpx.RequestExtractor by binding the query parameters into T.
+func (q *QueryStruct[T]) FromRequest(r *http.Request) error {
+	return QueryBinder{}.Bind(r, &q.value)
+}
+
+// Value returns the bound struct.
+func (q QueryStruct[T]) Value() T {
+	return q.value
 }
@@ -0,0 +1,44 @@
+package binding
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// typeDecoders holds the decoders registered with RegisterType, keyed by struct type.
+var typeDecoders sync.Map // map[reflect.Type]func(*http.Request, any) error
+
+// RegisterType registers decode as the decoder for requests bound into values of type t,
+// bypassing the content-type binders and field extractors entirely. It is an escape hatch
+// for performance-critical or unusual request types. t may be given as the struct type or
+// a pointer to it; decode always receives a pointer to the destination value.
+// Registering a type again replaces its decoder. It should be called during initialization.
+//
+// Example:
+//
+//	binding.RegisterType(reflect.TypeFor[Telemetry](), func(r *http.Request, dest any) error {
+//	    return decodeTelemetry(r.Body, dest.(*Telemetry))
+//	})
+func RegisterType(t reflect.Type, decode func(*http.Request, any) error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	typeDecoders.Store(t, decode)
+}
+
+// LookupType returns the decoder registered with RegisterType for the destination type t,
+// which is typically a pointer to the struct type that was registered.
+func LookupType(t reflect.Type) (func(*http.Request, any) error, bool) {
+	if t == nil {
+		return nil, false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	decode, ok := typeDecoders.Load(t)
+	if !ok {
+		return nil, false
+	}
+	return decode.(func(*http.Request, any) error), true
+}
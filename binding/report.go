@@ -0,0 +1,71 @@
+package binding
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// FieldReport describes how a single struct field was handled while binding.
+type FieldReport struct {
+	Field  string   // Name of the struct field
+	Key    string   // Key looked up in the request values, from the form tag or the field name
+	Type   string   // Target type the values were converted to, e.g. "int" or "[]string"
+	Found  bool     // Whether the key was present in the request values
+	Values []string // Raw values found for the key
+	Err    error    // Conversion error, if any
+}
+
+// BindReport records per-field diagnostics collected while binding form and query values.
+// It is intended for development, to tell apart missing sources from misnamed tags.
+type BindReport struct {
+	Fields []FieldReport
+}
+
+// Field returns the report for the named struct field.
+// The boolean is false if the field was not inspected.
+func (r *BindReport) Field(name string) (FieldReport, bool) {
+	for _, f := range r.Fields {
+		if f.Field == name {
+			return f, true
+		}
+	}
+	return FieldReport{}, false
+}
+
+// String returns a human readable summary with one line per field.
+func (r *BindReport) String() string {
+	var sb strings.Builder
+	for _, f := range r.Fields {
+		switch {
+		case f.Err != nil:
+			fmt.Fprintf(&sb, "%s (%s) <- %q %q: error: %v\n", f.Field, f.Type, f.Key, f.Values, f.Err)
+		case f.Found:
+			fmt.Fprintf(&sb, "%s (%s) <- %q %q\n", f.Field, f.Type, f.Key, f.Values)
+		default:
+			fmt.Fprintf(&sb, "%s (%s) <- %q: missing\n", f.Field, f.Type, f.Key)
+		}
+	}
+	return sb.String()
+}
+
+// add records a field report. It is a no-op on a nil report.
+func (r *BindReport) add(f FieldReport) {
+	if r != nil {
+		r.Fields = append(r.Fields, f)
+	}
+}
+
+// reportKey is the context key under which the BindReport is stored.
+type reportKey struct{}
+
+// WithReport returns a context that makes binders record diagnostics into report.
+func WithReport(ctx context.Context, report *BindReport) context.Context {
+	return context.WithValue(ctx, reportKey{}, report)
+}
+
+// ReportFromContext returns the BindReport stored by WithReport, or nil if there is none.
+func ReportFromContext(ctx context.Context) *BindReport {
+	report, _ := ctx.Value(reportKey{}).(*BindReport)
+	return report
+}
@@ -0,0 +1,73 @@
+package binding
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// SchemaViolation describes a single way in which a document fails to satisfy a schema.
+type SchemaViolation struct {
+	Path    string `json:"path"`    // Location of the offending value, e.g. "/user/email"
+	Message string `json:"message"` // Human readable description of the violation
+}
+
+// SchemaValidator validates raw JSON documents against a compiled schema.
+// It is implemented by adapters around a JSON Schema library of choice,
+// so the binding package does not depend on any particular implementation.
+type SchemaValidator interface {
+	// Validate returns the violations found in the document, or none if it is valid.
+	// An error is returned if the document could not be validated at all.
+	Validate(document []byte) ([]SchemaViolation, error)
+}
+
+// SchemaError is returned by SchemaBinder when the request body does not satisfy the schema.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = fmt.Sprintf("%s: %s", v.Path, v.Message)
+	}
+	return "binding: schema validation failed: " + strings.Join(messages, "; ")
+}
+
+// DefaultMaxSchemaBodySize is the default maximum number of bytes read by SchemaBinder.
+const DefaultMaxSchemaBodySize = 10 << 20
+
+// SchemaBinder validates JSON request bodies against a schema before binding them.
+// Valid bodies are bound with the embedded JSONBinder.
+type SchemaBinder struct {
+	JSONBinder
+	Validator SchemaValidator
+	// MaxBodySize is the maximum number of bytes read from the body.
+	// Defaults to DefaultMaxSchemaBodySize if not positive.
+	MaxBodySize int64
+}
+
+// Bind reads the request body, validates it with the Validator and, if valid,
+// binds it into a using the JSONBinder. Invalid bodies yield a *SchemaError.
+// If the body exceeds MaxBodySize, an *http.MaxBytesError is returned.
+func (s SchemaBinder) Bind(r *http.Request, a any) error {
+	maxBodySize := s.MaxBodySize
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultMaxSchemaBodySize
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+	violations, err := s.Validator.Validate(body)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &SchemaError{Violations: violations}
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	return s.JSONBinder.Bind(r, a)
+}
@@ -0,0 +1,71 @@
+package hx
+
+import "errors"
+
+// ErrContextValueMissing is returned by handlers created with GCtx when the request
+// context does not hold a value of the expected type under the given key.
+var ErrContextValueMissing = errors.New("hx: context value missing")
+
+// ErrPanic is wrapped by the error passed to the error handler when the router recovers
+// a panic raised by a handler.
+var ErrPanic = errors.New("hx: handler panicked")
+
+// ErrValidation is wrapped by the error returned by handlers created with JSONHandler
+// when the request fails its Validate method.
+var ErrValidation = errors.New("hx: validation failed")
+
+// BindError wraps the errors returned while binding a request, by ShouldBind, Bind and the
+// typed handlers, including those of RequestExtractor and RequestDecoder implementations.
+// The default error handler maps decoding failures, such as invalid JSON or a non-numeric value for
+// an int field, to 400 Bad Request only when they are wrapped in a BindError, so the same errors
+// returned by a handler, e.g. while decoding an upstream response, still yield a server error.
+type BindError struct {
+	Err error
+}
+
+func (e *BindError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error that occurred while binding.
+func (e *BindError) Unwrap() error {
+	return e.Err
+}
+
+// bindError wraps err in a BindError, unless it is nil or already wraps one.
+func bindError(err error) error {
+	var bindErr *BindError
+	if err == nil || errors.As(err, &bindErr) {
+		return err
+	}
+	return &BindError{Err: err}
+}
+
+// Errors joins multiple errors into a single error using errors.Join.
+// Nil errors are discarded, and nil is returned if every error is nil.
+// The default error handler renders a joined error as a JSON list of messages,
+// which makes it convenient for reporting several validation failures at once.
+// The response status is the one mapped for the first joined error that has one,
+// e.g. 400 Bad Request when it wraps ErrValidation, and 500 otherwise.
+//
+// Example:
+//
+//	return resp, hx.Errors(
+//	    validateName(req.Name),
+//	    validateEmail(req.Email),
+//	)
+func Errors(errs ...error) error {
+	return errors.Join(errs...)
+}
+
+// unwrapJoined returns the errors wrapped by a joined error, or nil if err does not wrap multiple errors.
+// A joined error returned while binding is unwrapped from its BindError.
+func unwrapJoined(err error) []error {
+	if bindErr, ok := err.(*BindError); ok {
+		err = bindErr.Err
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		return joined.Unwrap()
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package hx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	if err := Errors(nil, nil); err != nil {
+		t.Errorf("expected nil error, got %v", err)
+	}
+
+	r := New()
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		return Errors(
+			errors.New("name is required"),
+			nil,
+			errors.New("email is invalid"),
+			errors.New("age must be positive"),
+		)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	var body struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	expected := []string{"name is required", "email is invalid", "age must be positive"}
+	if len(body.Errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %d", len(expected), len(body.Errors))
+	}
+	for i, msg := range body.Errors {
+		if msg != expected[i] {
+			t.Errorf("expected error %d to be %s, got %s", i, expected[i], msg)
+		}
+	}
+}
+
+func TestErrorsMappedStatus(t *testing.T) {
+	r := New()
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		return Errors(
+			errors.New("unmapped"),
+			fmt.Errorf("%w: name is required", ErrValidation),
+			fmt.Errorf("%w: email is invalid", ErrValidation),
+		)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var body struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	expected := []string{"unmapped", "hx: validation failed: name is required", "hx: validation failed: email is invalid"}
+	if len(body.Errors) != len(expected) {
+		t.Fatalf("expected %d errors, got %d", len(expected), len(body.Errors))
+	}
+	for i, msg := range body.Errors {
+		if msg != expected[i] {
+			t.Errorf("expected error %d to be %s, got %s", i, expected[i], msg)
+		}
+	}
+}
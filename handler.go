@@ -4,6 +4,8 @@ package hx
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"reflect"
 	"unsafe"
@@ -23,6 +25,37 @@ type ErrorHandler func(w http.ResponseWriter, r *http.Request, err error)
 // If an error is returned, it will be passed to the ErrorHandler for processing.
 type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
 
+// StdHandler adapts h into a standard http.Handler, so it can be used outside a Router,
+// e.g. with a plain http.ServeMux or libraries expecting an http.Handler.
+// Errors returned by h are passed to errHandler; a nil errHandler uses the same defaults as
+// a Router created by New. Responses returned with Abort are rendered directly.
+// Panics are not recovered.
+//
+// Example:
+//
+//	mux.Handle("GET /users", hx.G(listUsers).JSON().StdHandler(nil))
+func (h HandlerFunc) StdHandler(errHandler ErrorHandler) http.Handler {
+	if errHandler == nil {
+		errHandler = New().ErrHandler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h(w, r); err != nil {
+			handleError(w, r, err, errHandler)
+		}
+	})
+}
+
+// handleError renders the response carried by an error created with Abort,
+// or passes err to errHandler otherwise.
+func handleError(w http.ResponseWriter, r *http.Request, err error, errHandler ErrorHandler) {
+	var abort *abortError
+	if errors.As(err, &abort) {
+		_ = abort.render.IntoResponse(w)
+		return
+	}
+	errHandler(w, r, err)
+}
+
 // Warp is a convenience function that wraps an http.HandlerFunc into a HandlerFunc.
 func Warp(h http.HandlerFunc) HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) error {
@@ -83,23 +116,303 @@ func E[Response any](h func(ctx context.Context) (Response, error)) TypedHandler
 	}
 }
 
+// GCtx creates a type-safe handler that also receives a typed value from the request context,
+// typically one injected by middleware such as WithValue. The value stored under key is
+// asserted to Ctx and passed to h alongside the request, removing repetitive ctx.Value casts.
+// If no value of type Ctx is stored under key, an error wrapping ErrContextValueMissing is returned.
+//
+// Example:
+//
+//	router.Use(hx.WithValue(userKey{}, currentUser))
+//	router.GET("/me", hx.GCtx[User](userKey{}, func(ctx context.Context, user User, req httpx.Empty) (User, error) {
+//	    return user, nil
+//	}).JSON())
+func GCtx[Ctx, Request, Response any](key any, h func(ctx context.Context, value Ctx, req Request) (Response, error)) TypedHandlerFunc[Request, Response] {
+	return func(ctx context.Context, req Request) (resp Response, err error) {
+		value, ok := ctx.Value(key).(Ctx)
+		if !ok {
+			return resp, fmt.Errorf("%w: %v", ErrContextValueMissing, key)
+		}
+		return h(ctx, value, req)
+	}
+}
+
+// P is a convenience function for creating a handler that only needs a single path parameter.
+// The named path parameter is read from the request and passed to h as a string,
+// removing the need to declare a request struct for trivial routes.
+//
+// Example:
+//
+//	router.GET("/user/{id}", hx.P("id", func(ctx context.Context, id string) (User, error) {
+//	    return findUser(ctx, id)
+//	}).JSON())
+func P[Response any](param string, h func(ctx context.Context, value string) (Response, error)) TypedHandlerFunc[PathParamRequest, Response] {
+	return func(ctx context.Context, req PathParamRequest) (Response, error) {
+		return h(ctx, req.request.PathValue(param))
+	}
+}
+
+// PathParamRequest is the request type of the handlers created by P, so they can be named and stored,
+// e.g. as a TypedHandlerFunc[PathParamRequest, User] before choosing how to render them.
+// It keeps the incoming request so the path parameter can be looked up by name.
+type PathParamRequest struct {
+	request *http.Request
+}
+
+// FromRequest implements httpx.RequestExtractor.
+func (p *PathParamRequest) FromRequest(r *http.Request) error {
+	p.request = r
+	return nil
+}
+
 // TypedHandlerFunc is a generic handler function that processes requests of type Request
 // and returns responses of type Response. It operates within a context and may return an error.
 type TypedHandlerFunc[Request, Response any] func(context.Context, Request) (Response, error)
 
 // JSON converts the handler into a JSON response handler.
-// The response will be automatically serialized to JSON format.
+// The response will be automatically serialized to JSON format, with the status code
+// reported by StatusCoder when the response implements it.
+// If the response implements httpx.ResponseRender, it is rendered directly instead,
+// so a single handler can return, for example, either data or a redirect.
 func (h TypedHandlerFunc[Request, Response]) JSON() HandlerFunc {
+	asRender := responseAsRender[Response]()
 	var handler requestHandler[Request] = func(ctx context.Context, req Request) (httpx.ResponseRender, error) {
 		resp, err := h(ctx, req)
 		if err != nil {
 			return nil, err
 		}
-		return httpx.JSONResponse{Data: resp}, nil
+		if render, ok := asRender(resp); ok {
+			return render, nil
+		}
+		return httpx.JSONResponse{Data: resp, StatusCode: responseStatus(ctx, resp)}, nil
+	}
+	return handler.asHandlerFunc()
+}
+
+// Validator can be implemented by a request type to check itself once it has been bound.
+type Validator interface {
+	Validate() error
+}
+
+// JSONHandler packages the common case of a JSON endpoint: it is G(h).JSON(), with the request
+// bound from path parameters, query and body as usual, and then validated when it implements
+// Validator. A validation failure is returned as an error wrapping ErrValidation, which the
+// default error handler renders as 400 Bad Request, and h is not called.
+//
+// Example:
+//
+//	type UpdateUser struct {
+//	    ID   int    `path:"id"`
+//	    Name string `json:"name"`
+//	}
+//
+//	func (u UpdateUser) Validate() error {
+//	    if u.Name == "" {
+//	        return errors.New("name is required")
+//	    }
+//	    return nil
+//	}
+//
+//	router.PUT("/users/{id}", hx.JSONHandler(updateUser))
+func JSONHandler[Request, Response any](h TypedHandlerFunc[Request, Response]) HandlerFunc {
+	return h.Pipe(validateRequest[Request]).JSON()
+}
+
+// validateRequest runs the Validate method of requests implementing Validator.
+func validateRequest[Request any](_ context.Context, req Request) error {
+	validator, ok := any(req).(Validator)
+	if !ok {
+		validator, ok = any(&req).(Validator)
+	}
+	if !ok {
+		return nil
+	}
+	if err := validator.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+	return nil
+}
+
+// Result converts the handler into a handler that renders both its value and its error in the
+// uniform httpx.Result envelope, {"data": ..., "error": ...}, instead of passing errors to the
+// error handler. Successful responses use 200 OK, or the status chosen with SetStatus or StatusCoder.
+// Errors use the status reported by an error implementing StatusCoder, then the mappings of the
+// router's error handler, including those added with WithErrorStatus, such as 400 Bad Request
+// for ErrValidation, then 500.
+// Errors binding the request still reach the error handler.
+func (h TypedHandlerFunc[Request, Response]) Result() HandlerFunc {
+	var handler requestHandler[Request] = func(ctx context.Context, req Request) (httpx.ResponseRender, error) {
+		resp, err := h(ctx, req)
+		if err != nil {
+			return httpx.Result[Response]{Err: err, StatusCode: resultErrorStatus(ctx, err)}, nil
+		}
+		return httpx.Result[Response]{Data: resp, StatusCode: responseStatus(ctx, resp)}, nil
+	}
+	return handler.asHandlerFunc()
+}
+
+// resultErrorStatus returns the status code Result renders err with.
+func resultErrorStatus(ctx context.Context, err error) int {
+	var coder StatusCoder
+	if errors.As(err, &coder) {
+		return coder.StatusCode()
+	}
+	if status, ok := errorStatusFor(ctx, err); ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// JSONOr204 converts the handler into a handler that responds with 204 No Content when the
+// response is the zero value of its type, such as a nil pointer or an empty struct, and renders it
+// like JSON otherwise. It suits DELETE endpoints that only sometimes return the deleted entity.
+//
+// Example:
+//
+//	router.DELETE("/users/{id}", hx.G(func(ctx context.Context, req DeleteUser) (*User, error) {
+//	    if !req.Return {
+//	        return nil, users.Delete(ctx, req.ID)
+//	    }
+//	    return users.Remove(ctx, req.ID)
+//	}).JSONOr204())
+func (h TypedHandlerFunc[Request, Response]) JSONOr204() HandlerFunc {
+	asRender := responseAsRender[Response]()
+	var handler requestHandler[Request] = func(ctx context.Context, req Request) (httpx.ResponseRender, error) {
+		resp, err := h(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.ValueOf(&resp).Elem().IsZero() {
+			return httpx.NoContentResponse{}, nil
+		}
+		if render, ok := asRender(resp); ok {
+			return render, nil
+		}
+		return httpx.JSONResponse{Data: resp, StatusCode: responseStatus(ctx, resp)}, nil
+	}
+	return handler.asHandlerFunc()
+}
+
+// Paginator can be implemented by a request type, with a value or pointer receiver,
+// to report the page it asks for, so Paginated can include it in the response envelope.
+type Paginator interface {
+	// PageAndSize returns the 1-based page number and the page size.
+	PageAndSize() (page, size int)
+}
+
+// Paginated creates a handler for list endpoints that renders the items and total returned by h
+// in the httpx.PaginatedResponse envelope, {"items": ..., "total": ..., "page": ..., "size": ...}.
+// The page and size are taken from the request when it implements Paginator; otherwise, or when
+// they are not positive, the page defaults to 1 and the size to the number of items returned.
+// It is a function rather than a TypedHandlerFunc method because methods cannot introduce the item type T.
+//
+// Example:
+//
+//	type ListUsers struct {
+//	    Page int `form:"page"`
+//	    Size int `form:"size"`
+//	}
+//
+//	func (l ListUsers) PageAndSize() (int, int) { return l.Page, l.Size }
+//
+//	router.GET("/users", hx.Paginated(func(ctx context.Context, req ListUsers) ([]User, int, error) {
+//	    return users.List(ctx, req.Page, req.Size)
+//	}))
+func Paginated[Request, T any](h func(ctx context.Context, req Request) (items []T, total int, err error)) HandlerFunc {
+	var handler requestHandler[Request] = func(ctx context.Context, req Request) (httpx.ResponseRender, error) {
+		items, total, err := h(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		var page, size int
+		paginator, ok := any(req).(Paginator)
+		if !ok {
+			paginator, ok = any(&req).(Paginator)
+		}
+		if ok {
+			page, size = paginator.PageAndSize()
+		}
+		if page <= 0 {
+			page = 1
+		}
+		if size <= 0 {
+			size = len(items)
+		}
+		return httpx.PaginatedResponse[T]{
+			Items:      items,
+			Total:      total,
+			Page:       page,
+			Size:       size,
+			StatusCode: statusFromContext(ctx),
+		}, nil
 	}
 	return handler.asHandlerFunc()
 }
 
+// StatusCoder can be implemented by a response type to choose the status code it is rendered with,
+// e.g. a type that always represents a newly created resource:
+//
+//	type CreatedUser struct{ User }
+//
+//	func (CreatedUser) StatusCode() int { return http.StatusCreated }
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// responseStatus returns the status code to render resp with: the one recorded by SetStatus,
+// otherwise the one reported by a StatusCoder response, otherwise 0 for the default.
+// A nil pointer response is rendered as null with the default status, since a value-receiver
+// StatusCode method cannot be called on it.
+func responseStatus(ctx context.Context, resp any) int {
+	if status := statusFromContext(ctx); status != 0 {
+		return status
+	}
+	if isNilPointer(resp) {
+		return 0
+	}
+	if coder, ok := resp.(StatusCoder); ok {
+		return coder.StatusCode()
+	}
+	return 0
+}
+
+// isNilPointer reports whether v holds a nil pointer, whose methods cannot be called safely.
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
+
+// Auto is an alias of JSON, kept for handlers returning either data or an httpx.ResponseRender
+// such as a redirect, which JSON renders directly.
+func (h TypedHandlerFunc[Request, Response]) Auto() HandlerFunc {
+	return h.JSON()
+}
+
+// responseRenderType is the reflect type for httpx.ResponseRender.
+var responseRenderType = reflect.TypeFor[httpx.ResponseRender]()
+
+// responseAsRender returns a function reporting whether a Response value should be rendered directly.
+// The type check is performed once: concrete types implementing httpx.ResponseRender are rendered
+// directly, interface types are checked per value, and other types never are. Nil pointers are
+// never rendered directly, since their IntoResponse method would typically dereference them.
+func responseAsRender[Response any]() func(Response) (httpx.ResponseRender, bool) {
+	responseType := reflect.TypeFor[Response]()
+	switch {
+	case responseType.Kind() != reflect.Interface && responseType.Implements(responseRenderType):
+		return func(resp Response) (httpx.ResponseRender, bool) {
+			render := any(resp).(httpx.ResponseRender)
+			return render, !isNilPointer(render)
+		}
+	case responseType.Kind() == reflect.Interface:
+		return func(resp Response) (httpx.ResponseRender, bool) {
+			render, ok := any(resp).(httpx.ResponseRender)
+			return render, ok && !isNilPointer(render)
+		}
+	default:
+		return func(Response) (httpx.ResponseRender, bool) { return nil, false }
+	}
+}
+
 // String converts the handler into a string response handler.
 // This method panics if the Response type is not string.
 func (h TypedHandlerFunc[Request, Response]) String() HandlerFunc {
@@ -112,7 +425,7 @@ func (h TypedHandlerFunc[Request, Response]) String() HandlerFunc {
 			return nil, err
 		}
 		str := *(*string)(unsafe.Pointer(&resp))
-		return httpx.StringResponse{Data: str}, nil
+		return httpx.StringResponse{Data: str, StatusCode: statusFromContext(ctx)}, nil
 	}
 	return handler.asHandlerFunc()
 }
@@ -125,7 +438,7 @@ func (h TypedHandlerFunc[Request, Response]) XML() HandlerFunc {
 		if err != nil {
 			return nil, err
 		}
-		return httpx.XMLResponse{Data: resp}, nil
+		return httpx.XMLResponse{Data: resp, StatusCode: statusFromContext(ctx)}, nil
 	}
 	return handler.asHandlerFunc()
 }
@@ -161,7 +474,7 @@ type requestHandler[Request any] func(context.Context, Request) (httpx.ResponseR
 
 // call executes the handler with the given request and writes the response.
 func (h requestHandler[Request]) call(w http.ResponseWriter, r *http.Request, req Request) error {
-	resp, err := h(r.Context(), req)
+	resp, err := h(withStatusHolder(r.Context()), req)
 	if err != nil {
 		return err
 	}
@@ -169,8 +482,16 @@ func (h requestHandler[Request]) call(w http.ResponseWriter, r *http.Request, re
 }
 
 // asHandlerFunc converts the requestHandler into a standard HandlerFunc.
-// It automatically determines whether to use extraction or binding based on the Request type.
+// It automatically determines whether to use decoding, extraction or binding based on the Request type.
 func (h requestHandler[Request]) asHandlerFunc() HandlerFunc {
+	requestType := reflect.TypeFor[Request]()
+	if requestType.Kind() != reflect.Pointer {
+		requestType = reflect.PointerTo(requestType)
+	}
+	if requestType.Implements(requestDecoderType) {
+		return h.decodeAndHandle()
+	}
+
 	isImplementRequestExtractor := httpx.IsRequestExtractorType(reflect.TypeFor[Request]())
 
 	if isImplementRequestExtractor {
@@ -198,7 +519,15 @@ func (h requestHandler[Request]) createHandler(extractFunc func(any, *http.Reque
 		return *new(Request)
 	}
 
+	// only walk the request for context enrichers when its type may contain one
+	hasEnricher := containsContextEnricher(requestType, make(map[reflect.Type]struct{}))
+
 	return func(w http.ResponseWriter, r *http.Request) error {
+		// reuse the value bound by BindFirst, its context values are already in place
+		if bound, ok := Bound[Request](r.Context()); ok {
+			return h.call(w, r, bound)
+		}
+
 		request := newRequest()
 		bindTarget := any(&request)
 		if isPointer {
@@ -206,12 +535,93 @@ func (h requestHandler[Request]) createHandler(extractFunc func(any, *http.Reque
 		}
 
 		if err := extractFunc(bindTarget, r); err != nil {
-			return err
+			return bindError(err)
+		}
+		if hasEnricher {
+			r = r.WithContext(enrichContext(r.Context(), reflect.ValueOf(bindTarget), make(map[enrichVisit]struct{})))
 		}
 		return h.call(w, r, request)
 	}
 }
 
+// contextEnricherType is the reflect type for httpx.ContextEnricher.
+var contextEnricherType = reflect.TypeFor[httpx.ContextEnricher]()
+
+// containsContextEnricher reports whether t, or any struct field reachable from it,
+// implements httpx.ContextEnricher.
+func containsContextEnricher(t reflect.Type, visiting map[reflect.Type]struct{}) bool {
+	if t.Kind() != reflect.Pointer {
+		t = reflect.PointerTo(t)
+	}
+	if t.Implements(contextEnricherType) {
+		return true
+	}
+	elem := t.Elem()
+	if elem.Kind() != reflect.Struct {
+		return false
+	}
+	if _, ok := visiting[elem]; ok {
+		return false
+	}
+	visiting[elem] = struct{}{}
+	for i := 0; i < elem.NumField(); i++ {
+		if containsContextEnricher(elem.Field(i).Type, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// enrichVisit identifies a value already visited by enrichContext.
+type enrichVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// enrichContext calls EnrichContext on v and on every settable struct field reachable from it
+// that implements httpx.ContextEnricher, threading the context through each call.
+// v must be a pointer.
+func enrichContext(ctx context.Context, v reflect.Value, visited map[enrichVisit]struct{}) context.Context {
+	if v.IsNil() {
+		return ctx
+	}
+	// guard against cycles introduced by self-referencing pointers
+	key := enrichVisit{ptr: v.Pointer(), typ: v.Type()}
+	if _, ok := visited[key]; ok {
+		return ctx
+	}
+	visited[key] = struct{}{}
+
+	if enricher, ok := reflect.TypeAssert[httpx.ContextEnricher](v); ok {
+		ctx = enricher.EnrichContext(ctx)
+	}
+	elem := v.Elem()
+	if elem.Kind() != reflect.Struct {
+		return ctx
+	}
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+		if field.Kind() != reflect.Pointer {
+			field = field.Addr()
+		}
+		ctx = enrichContext(ctx, field, visited)
+	}
+	return ctx
+}
+
+// requestDecoderType is the reflect type for httpx.RequestDecoder.
+var requestDecoderType = reflect.TypeFor[httpx.RequestDecoder]()
+
+// decodeAndHandle creates a HandlerFunc that decodes request data using the RequestDecoder interface.
+func (h requestHandler[Request]) decodeAndHandle() HandlerFunc {
+	return h.createHandler(func(target any, r *http.Request) error {
+		return target.(httpx.RequestDecoder).DecodeRequest(r)
+	})
+}
+
 // extractAndHandle creates a HandlerFunc that extracts request data using the RequestExtractor interface.
 func (h requestHandler[Request]) extractAndHandle() HandlerFunc {
 	return h.createHandler(func(target any, r *http.Request) error {
@@ -226,14 +636,116 @@ func (h requestHandler[Request]) bindAndHandle() HandlerFunc {
 	})
 }
 
+// binderKey is the context key under which UseBinder stores the binder override.
+type binderKey struct{}
+
+// UseBinder is a middleware that makes ShouldBind use binder instead of the default
+// Content-Type based binder. Wrapping a single handler opts that handler into the binder:
+//
+//	r.POST("/users", hx.UseBinder(binding.SchemaBinder{Validator: userSchema})(hx.G(createUser).JSON()))
+func UseBinder(binder binding.Binder) Middleware {
+	return WithValue(binderKey{}, binder)
+}
+
 // ShouldBind binds the request data to the given interface.
-// It first tries to bind using the default binder based on Content-Type,
-// then attempts to bind using the GenericBinder if the type implements RequestExtractor.
+// If a decoder was registered for the type of e with binding.RegisterType, it is used exclusively.
+// If e implements RequestDecoder, it decodes itself and no binder is used.
+// Otherwise it first tries to bind using the binder set by UseBinder, or the default binder based on Content-Type,
+// then binds "path" tagged fields, then attempts to bind using the GenericBinder if the type implements RequestExtractor.
+// Errors are wrapped in a *BindError.
 func ShouldBind(r *http.Request, e any) error {
-	binder := binding.Default(r.Method, r.Header.Get("Content-Type"))
+	if decode, ok := binding.LookupType(reflect.TypeOf(e)); ok {
+		return bindError(decode(r, e))
+	}
+	if decoder, ok := e.(httpx.RequestDecoder); ok {
+		return bindError(decoder.DecodeRequest(r))
+	}
+	binder, ok := r.Context().Value(binderKey{}).(binding.Binder)
+	if !ok {
+		binder = binding.Default(r.Method, r.Header.Get("Content-Type"))
+	}
+	return ShouldBindWith(r, e, binder)
+}
+
+// Bind allocates a value of type T, binds the request into it and returns it.
+// It dispatches like the typed handlers do: types implementing RequestDecoder decode themselves,
+// types implementing RequestExtractor are extracted, and everything else goes through ShouldBind.
+// If T is a pointer type, a new value is allocated for it to point to.
+// It is meant for plain HandlerFuncs that do not use G:
+//
+//	func search(w http.ResponseWriter, r *http.Request) error {
+//	    req, err := hx.Bind[SearchRequest](r)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+func Bind[T any](r *http.Request) (T, error) {
+	var value T
+	target := any(&value)
+	if t := reflect.TypeFor[T](); t.Kind() == reflect.Pointer {
+		elem := reflect.New(t.Elem())
+		value, _ = reflect.TypeAssert[T](elem)
+		target = value
+	}
+
+	// ShouldBind handles registered types and RequestDecoder itself
+	_, isDecoder := target.(httpx.RequestDecoder)
+	if extractor, ok := target.(httpx.RequestExtractor); ok && !isDecoder {
+		return value, bindError(extractor.FromRequest(r))
+	}
+	return value, ShouldBind(r, target)
+}
+
+// ShouldBindDebug is like ShouldBind but also returns a report describing, for each form or
+// query field, whether a value was found and what it was converted to.
+// It is meant for diagnosing binding issues during development.
+func ShouldBindDebug(r *http.Request, e any) (*binding.BindReport, error) {
+	report := &binding.BindReport{}
+	err := ShouldBind(r.WithContext(binding.WithReport(r.Context(), report)), e)
+	return report, err
+}
+
+// ShouldBindWith binds the request data to the given interface using the given binder,
+// then binds fields tagged with "path" from the route's path parameters,
+// and finally attempts to bind using the GenericBinder if the type implements RequestExtractor.
+// Errors are wrapped in a *BindError.
+// Combined with binding.ForContentType it forces body binding regardless of the method:
+//
+//	err := hx.ShouldBindWith(r, &req, binding.ForContentType(r.Header.Get("Content-Type")))
+func ShouldBindWith(r *http.Request, e any, binder binding.Binder) error {
 	if err := binder.Bind(r, e); err != nil {
-		return err
+		return bindError(err)
+	}
+	if err := binding.Path().Bind(r, e); err != nil {
+		return bindError(err)
 	}
 	// if each field has implemented RequestExtractor
-	return binding.Generic().Bind(r, e)
+	return bindError(binding.Generic().Bind(r, e))
+}
+
+// abortError carries the response rendered by the router for an error created with Abort.
+type abortError struct {
+	render httpx.ResponseRender
+}
+
+func (a *abortError) Error() string {
+	return "hx: request aborted"
+}
+
+// Abort returns an error that makes the router render the given response directly,
+// bypassing ErrHandler. It lets middleware and handlers reject a request with an exact response:
+//
+//	func RequireAuth(next hx.HandlerFunc) hx.HandlerFunc {
+//	    return func(w http.ResponseWriter, r *http.Request) error {
+//	        if r.Header.Get("Authorization") == "" {
+//	            return hx.Abort(httpx.JSONResponse{Data: map[string]string{"error": "unauthorized"}, StatusCode: http.StatusUnauthorized})
+//	        }
+//	        return next(w, r)
+//	    }
+//	}
+//
+// The error is detected with errors.As, so it may be wrapped.
+func Abort(render httpx.ResponseRender) error {
+	return &abortError{render: render}
 }
@@ -5,10 +5,15 @@ import (
 	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/eatmoreapple/hx/binding"
 	"github.com/eatmoreapple/hx/httpx"
 )
 
@@ -248,3 +253,756 @@ func TestE(t *testing.T) {
 		t.Errorf("expected body %s, got %s", "ok", w.Body.String())
 	}
 }
+
+func TestP(t *testing.T) {
+	var handler TypedHandlerFunc[PathParamRequest, string] = P("id", func(ctx context.Context, id string) (string, error) {
+		return "user " + id, nil
+	})
+
+	r := New()
+	r.GET("/user/{id}", handler.String())
+
+	req := httptest.NewRequest(http.MethodGet, "/user/42", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "user 42" {
+		t.Errorf("expected body %s, got %s", "user 42", w.Body.String())
+	}
+}
+
+type userKey struct{}
+
+type authUser struct {
+	name string
+}
+
+func (a *authUser) FromRequest(r *http.Request) error {
+	a.name = r.Header.Get("X-User")
+	return nil
+}
+
+func (a *authUser) EnrichContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, userKey{}, a.name)
+}
+
+func TestContextEnricher(t *testing.T) {
+	type Request struct {
+		User authUser
+	}
+
+	handler := G(func(ctx context.Context, req Request) (string, error) {
+		user, _ := ctx.Value(userKey{}).(string)
+		return user, nil
+	}).String()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-User", "gopher")
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Body.String() != "gopher" {
+		t.Errorf("expected body %s, got %s", "gopher", w.Body.String())
+	}
+}
+
+type filePath string
+
+func (filePath) ValueName() string { return "path" }
+
+func TestPathWildcard(t *testing.T) {
+	type Request struct {
+		Path httpx.FromPath[filePath]
+	}
+
+	r := New()
+	g := r.Group("/static")
+	g.GET("/files/{path...}", G(func(ctx context.Context, req Request) (string, error) {
+		return req.Path.String(), nil
+	}).String())
+
+	req := httptest.NewRequest(http.MethodGet, "/static/files/docs/guide/intro.md", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "docs/guide/intro.md" {
+		t.Errorf("expected body %s, got %s", "docs/guide/intro.md", w.Body.String())
+	}
+}
+
+type upperBinder struct{}
+
+func (upperBinder) Bind(r *http.Request, a any) error {
+	a.(*struct{ Name string }).Name = "FORCED"
+	return nil
+}
+
+func TestUseBinder(t *testing.T) {
+	handler := UseBinder(upperBinder{})(G(func(ctx context.Context, req struct{ Name string }) (string, error) {
+		return req.Name, nil
+	}).String())
+
+	req := httptest.NewRequest(http.MethodGet, "/?Name=query", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Body.String() != "FORCED" {
+		t.Errorf("expected body %s, got %s", "FORCED", w.Body.String())
+	}
+}
+
+// pairRequest decodes itself from a "key:value" per line body.
+type pairRequest struct {
+	Name string
+	Age  string
+}
+
+func (p *pairRequest) DecodeRequest(r *http.Request) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	for line := range strings.Lines(string(body)) {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			return errors.New("malformed line")
+		}
+		switch key {
+		case "name":
+			p.Name = value
+		case "age":
+			p.Age = value
+		}
+	}
+	return nil
+}
+
+func TestRequestDecoder(t *testing.T) {
+	handler := G(func(ctx context.Context, req pairRequest) (string, error) {
+		return req.Name + "/" + req.Age, nil
+	}).String()
+
+	// the JSON content type must be ignored in favor of DecodeRequest
+	req := httptest.NewRequest(http.MethodPost, "/?Name=query", strings.NewReader("name:alice\nage:30"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Body.String() != "alice/30" {
+		t.Errorf("expected body %s, got %s", "alice/30", w.Body.String())
+	}
+
+	var decoded pairRequest
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("bad"))
+	if err := ShouldBind(req, &decoded); err == nil || err.Error() != "malformed line" {
+		t.Errorf("expected malformed line error, got %v", err)
+	}
+}
+
+func TestRawBody(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) error {
+		var raw httpx.RawBody
+		if err := raw.FromRequest(r); err != nil {
+			return err
+		}
+
+		var payload struct {
+			Name string `json:"name"`
+		}
+		if err := ShouldBind(r, &payload); err != nil {
+			return err
+		}
+
+		if raw.String() != `{"name":"gopher"}` {
+			t.Errorf("expected raw body %q, got %q", `{"name":"gopher"}`, raw.String())
+		}
+		if payload.Name != "gopher" {
+			t.Errorf("expected name %s, got %s", "gopher", payload.Name)
+		}
+		return nil
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := handler(httptest.NewRecorder(), req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+type telemetry struct {
+	Points []string `json:"points"`
+}
+
+func TestRegisterType(t *testing.T) {
+	binding.RegisterType(reflect.TypeFor[telemetry](), func(r *http.Request, dest any) error {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		dest.(*telemetry).Points = strings.Split(string(body), "|")
+		return nil
+	})
+
+	handler := G(func(ctx context.Context, req telemetry) (int, error) {
+		return len(req.Points), nil
+	}).JSON()
+
+	// the JSON binder would fail on this body
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("a|b|c"))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Body.String() != "3\n" {
+		t.Errorf("expected body %q, got %q", "3\n", w.Body.String())
+	}
+}
+
+type createdUser struct {
+	Name string `json:"name"`
+}
+
+func (createdUser) StatusCode() int { return http.StatusCreated }
+
+func TestStatusCoder(t *testing.T) {
+	handler := G(func(ctx context.Context, req struct{ Name string }) (createdUser, error) {
+		return createdUser{Name: req.Name}, nil
+	}).JSON()
+
+	req := httptest.NewRequest(http.MethodGet, "/?Name=gopher", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	if w.Body.String() != "{\"name\":\"gopher\"}\n" {
+		t.Errorf("expected body %q, got %q", "{\"name\":\"gopher\"}\n", w.Body.String())
+	}
+}
+
+func TestBind(t *testing.T) {
+	type Search struct {
+		Query string `form:"q" json:"q"`
+		Page  int    `form:"page" json:"page"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?q=go&page=2", nil)
+	search, err := Bind[Search](req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if search.Query != "go" || search.Page != 2 {
+		t.Errorf("unexpected query binding: %+v", search)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"q":"hx","page":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	ptr, err := Bind[*Search](req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ptr == nil || ptr.Query != "hx" || ptr.Page != 3 {
+		t.Errorf("unexpected body binding: %+v", ptr)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?page=x", nil)
+	if _, err := Bind[Search](req); err == nil {
+		t.Error("expected binding error")
+	}
+}
+
+func TestBindFirst(t *testing.T) {
+	type Request struct {
+		User authUser
+		Item string `json:"item"`
+	}
+
+	var seen string
+	postBind := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			seen, _ = r.Context().Value(userKey{}).(string)
+			return next(w, r)
+		}
+	}
+
+	handler := Chain(BindFirst[Request](), postBind)(G(func(ctx context.Context, req Request) (string, error) {
+		return req.User.name + " ordered " + req.Item, nil
+	}).String())
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"item":"tea"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User", "gopher")
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if seen != "gopher" {
+		t.Errorf("expected middleware to see user %q, got %q", "gopher", seen)
+	}
+
+	// the handler reuses the bound value although the body was consumed
+	if w.Body.String() != "gopher ordered tea" {
+		t.Errorf("expected body %q, got %q", "gopher ordered tea", w.Body.String())
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	type Response struct {
+		ID int `json:"id"`
+	}
+
+	enqueue := func(ctx context.Context) Response {
+		SetStatus(ctx, http.StatusAccepted)
+		return Response{ID: 1}
+	}
+
+	handler := E(func(ctx context.Context) (Response, error) {
+		return enqueue(ctx), nil
+	}).JSON()
+
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("expected status code %d, got %d", http.StatusAccepted, w.Code)
+	}
+}
+
+func TestGCtx(t *testing.T) {
+	type User struct {
+		Name string
+	}
+	type Request struct {
+		Greeting string `form:"greeting"`
+	}
+
+	handler := GCtx[User](userKey{}, func(ctx context.Context, user User, req Request) (string, error) {
+		return req.Greeting + " " + user.Name, nil
+	}).String()
+
+	r := New()
+	r.GET("/me", WithValue(userKey{}, User{Name: "gopher"})(handler))
+
+	req := httptest.NewRequest(http.MethodGet, "/me?greeting=hello", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "hello gopher" {
+		t.Errorf("expected body %s, got %s", "hello gopher", w.Body.String())
+	}
+
+	var gotErr error
+	r = New(WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		gotErr = err
+	}))
+	r.GET("/anonymous", handler)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/anonymous", nil))
+
+	if !errors.Is(gotErr, ErrContextValueMissing) {
+		t.Errorf("expected ErrContextValueMissing, got %v", gotErr)
+	}
+}
+
+func TestShouldBindWith(t *testing.T) {
+	type Request struct {
+		Name string `json:"name" form:"name"`
+	}
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", strings.NewReader(`{"name":"gopher"}`))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	var data Request
+	if err := ShouldBind(newRequest(), &data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Name != "" {
+		t.Errorf("expected GET body to be ignored by default, got %s", data.Name)
+	}
+
+	req := newRequest()
+	if err := ShouldBindWith(req, &data, binding.ForContentType(req.Header.Get("Content-Type"))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if data.Name != "gopher" {
+		t.Errorf("expected name %s, got %s", "gopher", data.Name)
+	}
+}
+
+func TestShouldBindDebug(t *testing.T) {
+	type Request struct {
+		Name  string `form:"name"`
+		Age   int    `form:"age"`
+		Email string `form:"mail"`
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/?name=gopher&age=3&email=x@y.z", nil)
+
+	var data Request
+	report, err := ShouldBindDebug(req, &data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		field string
+		found bool
+	}{
+		{"Name", true},
+		{"Age", true},
+		{"Email", false},
+	}
+	for _, tt := range tests {
+		f, ok := report.Field(tt.field)
+		if !ok {
+			t.Fatalf("expected report for field %s", tt.field)
+		}
+		if f.Found != tt.found {
+			t.Errorf("expected field %s found=%v, got %v", tt.field, tt.found, f.Found)
+		}
+	}
+
+	if f, _ := report.Field("Age"); f.Type != "int" || len(f.Values) != 1 || f.Values[0] != "3" {
+		t.Errorf("unexpected report for Age: %+v", f)
+	}
+}
+
+func TestStatusCoderNilPointer(t *testing.T) {
+	handler := G(func(ctx context.Context, req struct{}) (*createdUser, error) {
+		return nil, nil
+	}).JSON()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusOK || w.Body.String() != "null\n" {
+		t.Errorf("expected null with status %d, got %d %q", http.StatusOK, w.Code, w.Body.String())
+	}
+}
+
+func TestJSONRendersResponseRender(t *testing.T) {
+	type Request struct {
+		Legacy bool `form:"legacy"`
+	}
+
+	handler := G(func(ctx context.Context, req Request) (any, error) {
+		if req.Legacy {
+			return httpx.RedirectResponse{URL: "/v2", StatusCode: http.StatusMovedPermanently}, nil
+		}
+		return map[string]string{"version": "v1"}, nil
+	}).JSON()
+
+	req := httptest.NewRequest(http.MethodGet, "/?legacy=true", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Errorf("expected status code %d, got %d", http.StatusMovedPermanently, w.Code)
+	}
+
+	if w.Header().Get("Location") != "/v2" {
+		t.Errorf("expected location %s, got %s", "/v2", w.Header().Get("Location"))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	w = httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Body.String() != "{\"version\":\"v1\"}\n" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestAutoRender(t *testing.T) {
+	handler := G(func(ctx context.Context, req struct{}) (any, error) {
+		return httpx.RedirectResponse{URL: "/v2", StatusCode: http.StatusFound}, nil
+	}).Auto()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusFound {
+		t.Errorf("expected status code %d, got %d", http.StatusFound, w.Code)
+	}
+
+	if w.Header().Get("Location") != "/v2" {
+		t.Errorf("expected location %s, got %s", "/v2", w.Header().Get("Location"))
+	}
+}
+
+func TestJSONNilResponseRender(t *testing.T) {
+	handler := G(func(ctx context.Context, req struct{}) (*httpx.RedirectResponse, error) {
+		return nil, nil
+	}).JSON()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Body.String() != "null\n" {
+		t.Errorf("unexpected body %q", w.Body.String())
+	}
+}
+
+func TestStdHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /users/{id}", P("id", func(ctx context.Context, id string) (map[string]string, error) {
+		if id == "0" {
+			return nil, errors.New("no such user")
+		}
+		return map[string]string{"id": id}, nil
+	}).JSON().StdHandler(nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Body.String() != "{\"id\":\"7\"}\n" {
+		t.Errorf("expected body %q, got %q", "{\"id\":\"7\"}\n", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/users/0", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	var handled error
+	handler := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("custom")
+	}).StdHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled = err
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if handled == nil || handled.Error() != "custom" {
+		t.Errorf("expected custom error handler to receive the error, got %v", handled)
+	}
+}
+
+type renameUser struct {
+	ID   int    `path:"id" json:"-"`
+	Name string `json:"name"`
+}
+
+// requiredFieldError is a typed validation error reporting a missing field.
+type requiredFieldError struct {
+	field string
+}
+
+func (e *requiredFieldError) Error() string { return e.field + " is required" }
+
+func (u renameUser) Validate() error {
+	if u.Name == "" {
+		return &requiredFieldError{field: "name"}
+	}
+	return nil
+}
+
+func TestJSONHandler(t *testing.T) {
+	r := New()
+	r.PUT("/users/{id}", JSONHandler(func(ctx context.Context, req renameUser) (map[string]any, error) {
+		return map[string]any{"id": req.ID, "name": req.Name}, nil
+	}))
+
+	send := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/users/7", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send(`{"name":"gopher"}`)
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"id":7,"name":"gopher"}` {
+		t.Errorf("unexpected response %d %q", w.Code, w.Body.String())
+	}
+
+	w = send(`{}`)
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "name is required") {
+		t.Errorf("expected validation failure, got %d %q", w.Code, w.Body.String())
+	}
+
+	err := validateRequest(context.Background(), renameUser{})
+	var fieldErr *requiredFieldError
+	if !errors.Is(err, ErrValidation) || !errors.As(err, &fieldErr) || fieldErr.field != "name" {
+		t.Errorf("expected ErrValidation wrapping the validator's error, got %v", err)
+	}
+}
+
+func TestResult(t *testing.T) {
+	type Lookup struct {
+		Name string `form:"name"`
+	}
+
+	errTaken := errors.New("name taken")
+	r := New(WithErrorStatus(errTaken, http.StatusConflict))
+	r.GET("/users", G(func(ctx context.Context, req Lookup) (map[string]string, error) {
+		switch req.Name {
+		case "":
+			return nil, fmt.Errorf("%w: name is required", ErrValidation)
+		case "root":
+			return nil, errTaken
+		}
+		return map[string]string{"name": req.Name}, nil
+	}).Result())
+
+	cases := []struct {
+		target string
+		status int
+		body   string
+	}{
+		{"/users?name=gopher", http.StatusOK, `{"data":{"name":"gopher"},"error":null}`},
+		{"/users", http.StatusBadRequest, `{"data":null,"error":"hx: validation failed: name is required"}`},
+		{"/users?name=root", http.StatusConflict, `{"data":null,"error":"name taken"}`},
+	}
+
+	for _, c := range cases {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, c.target, nil))
+
+		if w.Code != c.status || strings.TrimSpace(w.Body.String()) != c.body {
+			t.Errorf("%s: expected %d %s, got %d %s", c.target, c.status, c.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestJSONOr204(t *testing.T) {
+	type DeleteUser struct {
+		ID     int  `path:"id"`
+		Return bool `form:"return"`
+	}
+	type User struct {
+		ID int `json:"id"`
+	}
+
+	r := New()
+	r.DELETE("/users/{id}", G(func(ctx context.Context, req DeleteUser) (*User, error) {
+		if !req.Return {
+			return nil, nil
+		}
+		return &User{ID: req.ID}, nil
+	}).JSONOr204())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/3", nil))
+
+	if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+		t.Errorf("expected an empty 204 for a zero response, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/users/3?return=true", nil))
+
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"id":3}` {
+		t.Errorf("expected the deleted user, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+type listNumbers struct {
+	Page int `form:"page"`
+	Size int `form:"size"`
+}
+
+func (l listNumbers) PageAndSize() (int, int) { return l.Page, l.Size }
+
+func TestPaginated(t *testing.T) {
+	numbers := []int{1, 2, 3, 4, 5, 6, 7}
+
+	r := New()
+	r.GET("/numbers", Paginated(func(ctx context.Context, req listNumbers) ([]int, int, error) {
+		start := min((req.Page-1)*req.Size, len(numbers))
+		end := min(start+req.Size, len(numbers))
+		return numbers[start:end], len(numbers), nil
+	}))
+
+	tests := []struct {
+		target string
+		body   string
+	}{
+		{"/numbers?page=2&size=3", `{"items":[4,5,6],"total":7,"page":2,"size":3}`},
+		{"/numbers?page=4&size=3", `{"items":[],"total":7,"page":4,"size":3}`},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+		if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != tt.body {
+			t.Errorf("%s: expected %s, got %d %s", tt.target, tt.body, w.Code, w.Body.String())
+		}
+	}
+}
+
+type listNumbersByPointer struct {
+	Page int `form:"page"`
+	Size int `form:"size"`
+}
+
+func (l *listNumbersByPointer) PageAndSize() (int, int) { return l.Page, l.Size }
+
+func TestPaginatedPointerReceiver(t *testing.T) {
+	r := New()
+	r.GET("/numbers", Paginated(func(ctx context.Context, req listNumbersByPointer) ([]int, int, error) {
+		return []int{4, 5}, 5, nil
+	}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/numbers?page=2&size=3", nil))
+
+	expected := `{"items":[4,5],"total":5,"page":2,"size":3}`
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != expected {
+		t.Errorf("expected %s, got %d %s", expected, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,71 @@
+package extractor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DefaultMaxRawBodySize is the default maximum number of bytes read by RawBodyExtractor.
+const DefaultMaxRawBodySize = 10 << 20
+
+// maxRawBodySize is the maximum number of bytes read by RawBodyExtractor.
+var maxRawBodySize int64 = DefaultMaxRawBodySize
+
+// SetMaxRawBodySize sets the maximum number of bytes read by RawBodyExtractor.
+// It should be called during initialization, before any request is handled.
+// Panics if n is not positive.
+func SetMaxRawBodySize(n int64) {
+	if n <= 0 {
+		panic(fmt.Sprintf("extractor: max raw body size must be positive, got %d", n))
+	}
+	maxRawBodySize = n
+}
+
+// RawBodyExtractor implements RequestExtractor for the raw request body.
+// It reads the whole body, at most the size set by SetMaxRawBodySize, and replaces the
+// request body with a fresh reader over the same bytes, so binders running afterwards
+// still see the full body. It is typically used to verify webhook signatures.
+//
+// Body binders such as the JSON binder run before field extractors, so to combine
+// the raw bytes with body binding, extract them first and bind afterwards:
+//
+//	var raw httpx.RawBody
+//	if err := raw.FromRequest(r); err != nil {
+//	    return err
+//	}
+//	verify(raw.Bytes(), r.Header.Get("X-Signature"))
+//	err := hx.ShouldBind(r, &event)
+//
+// If the body exceeds the limit, an *http.MaxBytesError is returned.
+type RawBodyExtractor struct {
+	body []byte
+}
+
+// FromRequest implements RequestExtractor.FromRequest by reading the whole request body.
+func (r *RawBodyExtractor) FromRequest(request *http.Request) error {
+	if request.Body == nil || request.Body == http.NoBody {
+		r.body = nil
+		return nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(nil, request.Body, maxRawBodySize))
+	_ = request.Body.Close()
+	if err != nil {
+		return err
+	}
+	r.body = body
+	request.Body = io.NopCloser(bytes.NewReader(body))
+	return nil
+}
+
+// Bytes returns the raw body.
+// This method should be called after FromRequest has been executed successfully.
+func (r RawBodyExtractor) Bytes() []byte {
+	return r.body
+}
+
+// String returns the raw body as a string.
+func (r RawBodyExtractor) String() string {
+	return string(r.body)
+}
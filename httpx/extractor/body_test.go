@@ -0,0 +1,22 @@
+package extractor
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRawBodyExtractorLimit(t *testing.T) {
+	SetMaxRawBodySize(4)
+	defer SetMaxRawBodySize(DefaultMaxRawBodySize)
+
+	var raw RawBodyExtractor
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large"))
+
+	var maxBytesErr *http.MaxBytesError
+	if err := raw.FromRequest(req); !errors.As(err, &maxBytesErr) {
+		t.Errorf("expected *http.MaxBytesError, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package extractor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQueryExtractorHelpers(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?page=2&size=9000000000&ratio=0.5&debug=true&name=go", nil)
+
+	var q QueryExtractor
+	if err := q.FromRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v := q.Get("name"); v != "go" {
+		t.Errorf("expected name %s, got %s", "go", v)
+	}
+	if v := q.GetDefault("sort", "asc"); v != "asc" {
+		t.Errorf("expected default %s, got %s", "asc", v)
+	}
+	if v := q.GetDefault("name", "x"); v != "go" {
+		t.Errorf("expected name %s, got %s", "go", v)
+	}
+	if v, err := q.GetInt("page"); err != nil || v != 2 {
+		t.Errorf("expected page 2, got %d (%v)", v, err)
+	}
+	if v, err := q.GetInt64("size"); err != nil || v != 9000000000 {
+		t.Errorf("expected size 9000000000, got %d (%v)", v, err)
+	}
+	if v, err := q.GetFloat64("ratio"); err != nil || v != 0.5 {
+		t.Errorf("expected ratio 0.5, got %f (%v)", v, err)
+	}
+	if v, err := q.GetBool("debug"); err != nil || !v {
+		t.Errorf("expected debug true, got %v (%v)", v, err)
+	}
+	if _, err := q.GetInt("name"); err == nil {
+		t.Error("expected error parsing non-numeric value")
+	}
+}
+
+func TestFormExtractorHelpers(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/?page=3&debug=false", nil)
+	if err := req.ParseForm(); err != nil {
+		t.Fatal(err)
+	}
+
+	var f FormExtractor
+	if err := f.FromRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if v, err := f.GetInt("page"); err != nil || v != 3 {
+		t.Errorf("expected page 3, got %d (%v)", v, err)
+	}
+	if v, err := f.GetBool("debug"); err != nil || v {
+		t.Errorf("expected debug false, got %v (%v)", v, err)
+	}
+	if v := f.GetDefault("missing", "def"); v != "def" {
+		t.Errorf("expected default %s, got %s", "def", v)
+	}
+}
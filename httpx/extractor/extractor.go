@@ -1,6 +1,9 @@
 package extractor
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // RequestExtractor defines the interface for types that can extract data from HTTP requests.
 // Implementations should handle parsing and validating request data.
@@ -8,6 +11,21 @@ type RequestExtractor interface {
 	FromRequest(*http.Request) error
 }
 
+// RequestDecoder can be implemented by a request type with a bespoke wire format to decode
+// itself from the whole request. Unlike RequestExtractor, which is typically implemented per field,
+// a RequestDecoder takes over binding entirely: content-type binders and field extractors are skipped.
+type RequestDecoder interface {
+	DecodeRequest(*http.Request) error
+}
+
+// ContextEnricher can optionally be implemented by a RequestExtractor to contribute
+// values to the request context. It is called after extraction succeeds and before the
+// handler runs, so values resolved during extraction (e.g. the authenticated user)
+// become available through the handler's context.
+type ContextEnricher interface {
+	EnrichContext(context.Context) context.Context
+}
+
 type Empty struct{}
 
 func (e *Empty) FromRequest(*http.Request) error { return nil }
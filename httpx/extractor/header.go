@@ -1,6 +1,11 @@
 package extractor
 
-import "net/http"
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
 
 // HeaderValueExtractor implements RequestExtractor for HTTP header values.
 // It extracts and stores header values of a specified type T that implements the Value interface.
@@ -21,3 +26,58 @@ func (r *HeaderExtractor) FromRequest(request *http.Request) error {
 	*r = HeaderExtractor(request.Header)
 	return nil
 }
+
+// Get returns the first value associated with the given header key.
+func (r HeaderExtractor) Get(key string) string {
+	return http.Header(r).Get(key)
+}
+
+// Bearer returns the token from an "Authorization: Bearer <token>" header.
+// The scheme is matched case-insensitively. The boolean is false if the header
+// is missing, uses another scheme, or carries an empty token.
+func (r HeaderExtractor) Bearer() (string, bool) {
+	scheme, token, ok := strings.Cut(r.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+// ContentType returns the lower-cased media type of the Content-Type header without parameters.
+// For "application/json; charset=utf-8" it returns "application/json".
+// It returns an empty string if the header is missing or malformed.
+func (r HeaderExtractor) ContentType() string {
+	mediaType, _, err := mime.ParseMediaType(r.Get("Content-Type"))
+	if err != nil {
+		return ""
+	}
+	return mediaType
+}
+
+// Accepts reports whether the Accept header allows the given media type.
+// Wildcards such as "*/*" and "text/*" are honored, and entries with q=0 are treated as refusals.
+// A missing Accept header accepts everything.
+func (r HeaderExtractor) Accepts(mimeType string) bool {
+	accept := r.Get("Accept")
+	if accept == "" {
+		return true
+	}
+	mimeType = strings.ToLower(mimeType)
+	mainType, _, _ := strings.Cut(mimeType, "/")
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				continue
+			}
+		}
+		if mediaType == "*/*" || mediaType == mimeType || mediaType == mainType+"/*" {
+			return true
+		}
+	}
+	return false
+}
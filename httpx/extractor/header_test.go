@@ -0,0 +1,69 @@
+package extractor
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestHeaderExtractorBearer(t *testing.T) {
+	tests := []struct {
+		authorization string
+		token         string
+		ok            bool
+	}{
+		{"Bearer abc.def", "abc.def", true},
+		{"bearer abc", "abc", true},
+		{"Basic dXNlcjpwYXNz", "", false},
+		{"Bearer ", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		h := HeaderExtractor(http.Header{"Authorization": {tt.authorization}})
+		token, ok := h.Bearer()
+		if token != tt.token || ok != tt.ok {
+			t.Errorf("Bearer() for %q = (%q, %v), want (%q, %v)", tt.authorization, token, ok, tt.token, tt.ok)
+		}
+	}
+}
+
+func TestHeaderExtractorContentType(t *testing.T) {
+	tests := []struct {
+		contentType string
+		expected    string
+	}{
+		{"application/json; charset=utf-8", "application/json"},
+		{"Text/HTML", "text/html"},
+		{"", ""},
+		{"invalid;;", ""},
+	}
+
+	for _, tt := range tests {
+		h := HeaderExtractor(http.Header{"Content-Type": {tt.contentType}})
+		if got := h.ContentType(); got != tt.expected {
+			t.Errorf("ContentType() for %q = %q, want %q", tt.contentType, got, tt.expected)
+		}
+	}
+}
+
+func TestHeaderExtractorAccepts(t *testing.T) {
+	tests := []struct {
+		accept   string
+		mimeType string
+		expected bool
+	}{
+		{"text/html,application/xhtml+xml,*/*;q=0.8", "application/json", true},
+		{"application/json", "application/json", true},
+		{"application/json", "text/html", false},
+		{"text/*", "text/plain", true},
+		{"application/json;q=0", "application/json", false},
+		{"", "application/xml", true},
+	}
+
+	for _, tt := range tests {
+		h := HeaderExtractor(http.Header{"Accept": {tt.accept}})
+		if got := h.Accepts(tt.mimeType); got != tt.expected {
+			t.Errorf("Accepts(%q) for %q = %v, want %v", tt.mimeType, tt.accept, got, tt.expected)
+		}
+	}
+}
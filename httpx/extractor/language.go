@@ -0,0 +1,138 @@
+package extractor
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Languages is implemented by types listing the language tags an application supports,
+// such as "en", "en-US" or "fr". The first tag is the default when the client accepts none of them.
+type Languages interface {
+	SupportedLanguages() []string
+}
+
+// PreferredLanguageExtractor implements RequestExtractor for the Accept-Language header.
+// It picks the supported tag, as listed by T, that best matches the client's preferences.
+// The chosen tag is also stored in the handler's context, where LanguageFromContext retrieves it.
+type PreferredLanguageExtractor[T Languages] struct {
+	tag string
+}
+
+// FromRequest implements RequestExtractor.FromRequest by matching the Accept-Language header
+// against the tags supported by T.
+func (r *PreferredLanguageExtractor[T]) FromRequest(request *http.Request) error {
+	var languages T
+	r.tag = MatchLanguage(request.Header.Get("Accept-Language"), languages.SupportedLanguages())
+	return nil
+}
+
+// EnrichContext implements ContextEnricher by storing the chosen tag in ctx.
+func (r *PreferredLanguageExtractor[T]) EnrichContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, languageKey{}, r.tag)
+}
+
+// Tag returns the chosen language tag.
+func (r PreferredLanguageExtractor[T]) Tag() string {
+	return r.tag
+}
+
+// String returns the chosen language tag.
+func (r PreferredLanguageExtractor[T]) String() string {
+	return r.tag
+}
+
+// languageKey is the context key under which PreferredLanguageExtractor stores the chosen tag.
+type languageKey struct{}
+
+// LanguageFromContext returns the language tag chosen by a PreferredLanguageExtractor,
+// and false if the request had none.
+func LanguageFromContext(ctx context.Context) (string, bool) {
+	tag, ok := ctx.Value(languageKey{}).(string)
+	return tag, ok
+}
+
+// languageRange is an entry of an Accept-Language header.
+type languageRange struct {
+	tag string
+	q   float64
+}
+
+// MatchLanguage returns the tag of supported that best matches an Accept-Language header,
+// e.g. "fr-CH, fr;q=0.9, en;q=0.8". Ranges are tried by decreasing quality: a range matches
+// a supported tag that is equal to it, then one it is a prefix of ("en" matches "en-US"),
+// then one sharing its primary language ("en-GB" falls back to "en" or "en-US"), and "*" matches any tag.
+// Tags refused with q=0, along with the tags they are a prefix of, are never chosen by any of these rules.
+// Tags are compared case-insensitively. The first supported tag that is not refused is returned when
+// nothing matches, and an empty string when supported is empty.
+func MatchLanguage(header string, supported []string) string {
+	if len(supported) == 0 {
+		return ""
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		ranges = append(ranges, languageRange{tag: strings.ToLower(tag), q: q})
+	}
+	slices.SortStableFunc(ranges, func(a, b languageRange) int { return cmp.Compare(b.q, a.q) })
+
+	// a range with q=0 refuses its tag and the tags it is a prefix of, e.g. "fr;q=0" refuses "fr-CH"
+	excluded := func(tag string) bool {
+		tag = strings.ToLower(tag)
+		return slices.ContainsFunc(ranges, func(r languageRange) bool {
+			return r.q <= 0 && (r.tag == tag || strings.HasPrefix(tag, r.tag+"-"))
+		})
+	}
+
+	for _, r := range ranges {
+		if r.q <= 0 {
+			break
+		}
+		if r.tag == "*" {
+			for _, tag := range supported {
+				if !excluded(tag) {
+					return tag
+				}
+			}
+			continue
+		}
+		primary, _, _ := strings.Cut(r.tag, "-")
+		matchers := []func(tag string) bool{
+			func(tag string) bool { return tag == r.tag },
+			func(tag string) bool { return strings.HasPrefix(tag, r.tag+"-") },
+			func(tag string) bool {
+				tagPrimary, _, _ := strings.Cut(tag, "-")
+				return tagPrimary == primary
+			},
+		}
+		for _, match := range matchers {
+			for _, tag := range supported {
+				if match(strings.ToLower(tag)) && !excluded(tag) {
+					return tag
+				}
+			}
+		}
+	}
+	for _, tag := range supported {
+		if !excluded(tag) {
+			return tag
+		}
+	}
+	return supported[0]
+}
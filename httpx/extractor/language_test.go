@@ -0,0 +1,63 @@
+package extractor
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+)
+
+type testLanguages struct{}
+
+func (testLanguages) SupportedLanguages() []string { return []string{"en", "fr", "de-CH", "pt-BR"} }
+
+func TestMatchLanguage(t *testing.T) {
+	supported := testLanguages{}.SupportedLanguages()
+
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", "en"},
+		{"fr", "fr"},
+		{"fr-CH, fr;q=0.9, en;q=0.8", "fr"},
+		{"en;q=0.5, fr;q=0.8", "fr"},
+		{"DE", "de-CH"},
+		{"pt-PT;q=0.9, es", "pt-BR"},
+		{"en-GB", "en"},
+		{"ja, ko", "en"},
+		{"*, en;q=0", "fr"},
+		{"fr;q=0, *;q=0.5", "en"},
+		{"fr;q=abc, de-ch;q=0.1", "de-CH"},
+		{"fr-CH, fr;q=0", "en"},
+		{"de-AT, de;q=0, fr;q=0.5", "fr"},
+		{"ja, en;q=0", "fr"},
+	}
+
+	for _, tt := range tests {
+		if got := MatchLanguage(tt.header, supported); got != tt.expected {
+			t.Errorf("MatchLanguage(%q) = %q, want %q", tt.header, got, tt.expected)
+		}
+	}
+
+	if got := MatchLanguage("en", nil); got != "" {
+		t.Errorf("expected an empty tag without supported languages, got %q", got)
+	}
+}
+
+func TestPreferredLanguageExtractor(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept-Language", "de-DE, fr;q=0.7")
+
+	var lang PreferredLanguageExtractor[testLanguages]
+	if err := lang.FromRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lang.Tag() != "de-CH" {
+		t.Errorf("expected de-CH, got %q", lang.Tag())
+	}
+
+	if tag, ok := LanguageFromContext(lang.EnrichContext(context.Background())); !ok || tag != "de-CH" {
+		t.Errorf("expected de-CH in the context, got %q %v", tag, ok)
+	}
+}
@@ -0,0 +1,48 @@
+package extractor
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+)
+
+// NDJSONExtractor implements RequestExtractor for newline-delimited JSON request bodies.
+// Instead of loading every record into memory, it exposes an iterator that decodes
+// one record of type T at a time from the request body.
+//
+// Example:
+//
+//	for {
+//	    record, err := stream.Next()
+//	    if errors.Is(err, io.EOF) {
+//	        break
+//	    }
+//	    if err != nil {
+//	        return err
+//	    }
+//	    // process record
+//	}
+type NDJSONExtractor[T any] struct {
+	decoder *json.Decoder
+}
+
+// FromRequest implements RequestExtractor.FromRequest by preparing a decoder over the request body.
+// No data is read until Next is called.
+func (r *NDJSONExtractor[T]) FromRequest(request *http.Request) error {
+	if request.Body == nil {
+		return errors.New("extractor: request body is nil")
+	}
+	r.decoder = json.NewDecoder(request.Body)
+	return nil
+}
+
+// Next decodes and returns the next record from the stream.
+// It returns io.EOF once all records have been consumed.
+func (r *NDJSONExtractor[T]) Next() (T, error) {
+	var record T
+	if r.decoder == nil {
+		return record, errors.New("extractor: NDJSON stream is not initialized")
+	}
+	err := r.decoder.Decode(&record)
+	return record, err
+}
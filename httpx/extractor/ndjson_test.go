@@ -0,0 +1,40 @@
+package extractor
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNDJSONExtractor(t *testing.T) {
+	type Record struct {
+		ID int `json:"id"`
+	}
+
+	body := "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n"
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+
+	var stream NDJSONExtractor[Record]
+	if err := stream.FromRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []int
+	for {
+		record, err := stream.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, record.ID)
+	}
+
+	if len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 3 {
+		t.Errorf("expected ids [1 2 3], got %v", ids)
+	}
+}
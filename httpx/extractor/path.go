@@ -1,9 +1,22 @@
 package extractor
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// ErrInvalidPathValue is returned by converting path extractors when the path value
+// cannot be converted to the expected type.
+var ErrInvalidPathValue = errors.New("extractor: invalid path value")
 
 // PathValueExtractor implements RequestExtractor for path parameters.
 // It extracts named path values from HTTP requests using Go 1.22's Value feature.
+//
+// Wildcard segments are supported as well: for a route registered as "/files/{path...}",
+// a Value whose ValueName returns "path" receives the full remainder of the path,
+// e.g. "docs/guide/intro.md" for a request to /files/docs/guide/intro.md.
 type PathValueExtractor[T Value] struct {
 	baseValueExtractor[T]
 }
@@ -14,3 +27,66 @@ func (r *PathValueExtractor[T]) FromRequest(request *http.Request) error {
 	r.value = T(request.PathValue(r.value.ValueName()))
 	return nil
 }
+
+// paramValue is the value type backing PathParamExtractor.
+// Its name is supplied at construction, so ValueName is never consulted.
+type paramValue string
+
+// ValueName implements Value. PathParamExtractor carries its own name.
+func (paramValue) ValueName() string { return "" }
+
+// PathParamExtractor implements RequestExtractor for a path parameter whose name is
+// provided at construction instead of being derived from a Value type.
+// It is intended to be used directly inside handlers rather than as a struct field.
+type PathParamExtractor struct {
+	baseValueExtractor[paramValue]
+	name string
+}
+
+// NewPathParamExtractor creates a PathParamExtractor for the named path parameter.
+func NewPathParamExtractor(name string) *PathParamExtractor {
+	return &PathParamExtractor{name: name}
+}
+
+// Name returns the name of the path parameter.
+func (r *PathParamExtractor) Name() string {
+	return r.name
+}
+
+// FromRequest implements RequestExtractor.FromRequest by extracting the path value
+// from the request using the name provided at construction.
+func (r *PathParamExtractor) FromRequest(request *http.Request) error {
+	r.value = paramValue(request.PathValue(r.name))
+	return nil
+}
+
+// PathIntExtractor implements RequestExtractor for integer path parameters.
+// Unlike PathValueExtractor, the value is converted during FromRequest, so a request such as
+// /users/abc fails with an error wrapping ErrInvalidPathValue before the handler runs.
+type PathIntExtractor[T Value] struct {
+	value int64
+}
+
+// FromRequest implements RequestExtractor.FromRequest by parsing the path value
+// named by ValueName() as a base 10 integer.
+func (r *PathIntExtractor[T]) FromRequest(request *http.Request) error {
+	var name T
+	raw := request.PathValue(name.ValueName())
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %s %q is not an integer", ErrInvalidPathValue, name.ValueName(), raw)
+	}
+	r.value = v
+	return nil
+}
+
+// Value returns the converted value.
+// This method should be called after FromRequest has been executed successfully.
+func (r PathIntExtractor[T]) Value() int64 {
+	return r.value
+}
+
+// Int returns the converted value as an int.
+func (r PathIntExtractor[T]) Int() int {
+	return int(r.value)
+}
@@ -0,0 +1,33 @@
+package extractor
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPathParamExtractor(t *testing.T) {
+	var x, y string
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /a/{x}/b/{y}", func(w http.ResponseWriter, r *http.Request) {
+		px, py := NewPathParamExtractor("x"), NewPathParamExtractor("y")
+		if err := px.FromRequest(r); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if err := py.FromRequest(r); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		x, y = px.String(), py.String()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/a/1/b/2", nil)
+	mux.ServeHTTP(httptest.NewRecorder(), req)
+
+	if x != "1" {
+		t.Errorf("expected x %s, got %s", "1", x)
+	}
+	if y != "2" {
+		t.Errorf("expected y %s, got %s", "2", y)
+	}
+}
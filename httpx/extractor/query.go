@@ -28,3 +28,42 @@ func (r *QueryExtractor) FromRequest(request *http.Request) error {
 	*r = QueryExtractor(request.URL.Query())
 	return nil
 }
+
+// Get returns the first query value associated with the given key.
+// It returns an empty string if there are no values associated with the key.
+func (r QueryExtractor) Get(key string) string {
+	return url.Values(r).Get(key)
+}
+
+// GetDefault returns the first query value associated with the given key,
+// or def if the key is not present or its value is empty.
+func (r QueryExtractor) GetDefault(key, def string) string {
+	if value := r.Get(key); value != "" {
+		return value
+	}
+	return def
+}
+
+// GetInt returns the first query value associated with the given key converted to int.
+// Returns an error if the value cannot be parsed as an integer.
+func (r QueryExtractor) GetInt(key string) (int, error) {
+	return rawValue(r.Get(key)).Int()
+}
+
+// GetInt64 returns the first query value associated with the given key converted to int64.
+// Returns an error if the value cannot be parsed as an integer.
+func (r QueryExtractor) GetInt64(key string) (int64, error) {
+	return rawValue(r.Get(key)).Int64()
+}
+
+// GetFloat64 returns the first query value associated with the given key converted to float64.
+// Returns an error if the value cannot be parsed as a floating-point number.
+func (r QueryExtractor) GetFloat64(key string) (float64, error) {
+	return rawValue(r.Get(key)).Float64()
+}
+
+// GetBool returns the first query value associated with the given key converted to bool.
+// Returns an error if the value cannot be parsed as a boolean.
+func (r QueryExtractor) GetBool(key string) (bool, error) {
+	return rawValue(r.Get(key)).Bool()
+}
@@ -0,0 +1,33 @@
+package extractor
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrTrailerNotReady is returned when a trailer value is read before the request body
+// has been fully consumed. Trailers announced by the client are only populated once the
+// body has been read to EOF.
+var ErrTrailerNotReady = errors.New("extractor: trailer is not available until the request body is read")
+
+// TrailerValueExtractor implements RequestExtractor for HTTP trailer values.
+// It extracts and stores trailer values of a specified type T that implements the Value interface.
+//
+// Trailers are only available after the request body has been read, so this extractor
+// must run after anything that consumes the body. If the trailer was announced but the
+// body has not been read yet, FromRequest returns ErrTrailerNotReady.
+type TrailerValueExtractor[T Value] struct {
+	baseValueExtractor[T]
+}
+
+// FromRequest implements RequestExtractor.FromRequest by extracting the trailer value
+// using the name provided by ValueName(). The trailer value is converted to type T.
+func (r *TrailerValueExtractor[T]) FromRequest(request *http.Request) error {
+	name := http.CanonicalHeaderKey(r.value.ValueName())
+	// Announced trailers are present with no values until the body reaches EOF.
+	if values, ok := request.Trailer[name]; ok && len(values) == 0 {
+		return ErrTrailerNotReady
+	}
+	r.value = T(request.Trailer.Get(name))
+	return nil
+}
@@ -0,0 +1,39 @@
+package extractor
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type TestTrailer string
+
+func (t TestTrailer) ValueName() string {
+	return "X-Checksum"
+}
+
+func TestTrailerValueExtractor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload"))
+	req.Trailer = http.Header{"X-Checksum": nil}
+
+	var e TrailerValueExtractor[TestTrailer]
+	if err := e.FromRequest(req); !errors.Is(err, ErrTrailerNotReady) {
+		t.Fatalf("expected ErrTrailerNotReady, got %v", err)
+	}
+
+	// Simulate the server populating the trailer once the body is consumed.
+	if _, err := io.ReadAll(req.Body); err != nil {
+		t.Fatal(err)
+	}
+	req.Trailer.Set("X-Checksum", "abc123")
+
+	if err := e.FromRequest(req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.String() != "abc123" {
+		t.Errorf("expected trailer %s, got %s", "abc123", e.String())
+	}
+}
@@ -132,3 +132,9 @@ func (b baseValueExtractor[T]) String() string {
 func (b *baseValueExtractor[T]) FromRequest(*http.Request) error {
 	return errors.ErrUnsupported
 }
+
+// rawValue wraps a raw string in a baseValueExtractor so collection extractors
+// can reuse the same conversion methods as the single-value extractors.
+func rawValue(s string) baseValueExtractor[paramValue] {
+	return baseValueExtractor[paramValue]{value: paramValue(s)}
+}
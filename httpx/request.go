@@ -5,6 +5,7 @@
 package httpx
 
 import (
+	"context"
 	"reflect"
 	"sync"
 
@@ -15,6 +16,14 @@ import (
 // which defines methods for extracting data from HTTP requests.
 type RequestExtractor = extractor.RequestExtractor
 
+// ContextEnricher is an alias for extractor.ContextEnricher interface,
+// which lets extractors contribute values to the handler's context.
+type ContextEnricher = extractor.ContextEnricher
+
+// RequestDecoder is an alias for extractor.RequestDecoder interface,
+// which lets a request type decode itself from the whole request.
+type RequestDecoder = extractor.RequestDecoder
+
 // RequestExtractorType holds the reflection Type of the RequestExtractor interface.
 // This is used for runtime type checking and reflection-based operations
 // when determining if a type implements the RequestExtractor interface.
@@ -53,9 +62,14 @@ func IsRequestExtractorType(t reflect.Type) bool {
 // These provide convenient access to the underlying extractor implementations
 // while maintaining the package's cohesive API.
 type (
-	// FromPath is a shorthand for PathValueExtractor
+	// FromPath is a shorthand for PathValueExtractor.
+	// It also reads wildcard segments such as {path...}.
 	FromPath[T extractor.Value] = extractor.PathValueExtractor[T]
 
+	// PathInt is a shorthand for PathIntExtractor.
+	// The path value is parsed as an integer during extraction.
+	PathInt[T extractor.Value] = extractor.PathIntExtractor[T]
+
 	// FromHeader is a shorthand for HeaderValueExtractor
 	FromHeader[T extractor.Value] = extractor.HeaderValueExtractor[T]
 
@@ -67,8 +81,63 @@ type (
 
 	// FromCookie is a shorthand for CookieValueExtractor
 	FromCookie[T extractor.Value] = extractor.CookieValueExtractor[T]
+
+	// FromTrailer is a shorthand for TrailerValueExtractor
+	FromTrailer[T extractor.Value] = extractor.TrailerValueExtractor[T]
+
+	// PreferredLanguage is a shorthand for PreferredLanguageExtractor.
+	// It picks the tag listed by T that best matches the Accept-Language header.
+	PreferredLanguage[T extractor.Languages] = extractor.PreferredLanguageExtractor[T]
 )
 
+// Languages is an alias for extractor.Languages interface,
+// which lists the language tags supported by a PreferredLanguage extractor.
+type Languages = extractor.Languages
+
+// LanguageFromContext returns the language tag chosen by a PreferredLanguage extractor
+// for the current request, and false if the request type has none.
+//
+// Example:
+//
+//	type AppLanguages struct{}
+//
+//	func (AppLanguages) SupportedLanguages() []string { return []string{"en", "fr", "de"} }
+//
+//	type GreetRequest struct {
+//	    Lang httpx.PreferredLanguage[AppLanguages]
+//	}
+//
+//	// In the handler, or in code it calls:
+//	lang, _ := httpx.LanguageFromContext(ctx)
+func LanguageFromContext(ctx context.Context) (string, bool) {
+	return extractor.LanguageFromContext(ctx)
+}
+
+// MatchLanguage returns the tag of supported that best matches an Accept-Language header,
+// defaulting to the first supported tag. See extractor.MatchLanguage for the matching rules.
+func MatchLanguage(header string, supported []string) string {
+	return extractor.MatchLanguage(header, supported)
+}
+
+// ErrInvalidPathValue is returned when a converting path extractor such as PathInt
+// receives a value that cannot be converted.
+var ErrInvalidPathValue = extractor.ErrInvalidPathValue
+
+// PathParam creates an extractor for the named path parameter.
+// Unlike FromPath, the name is given at construction, so several parameters
+// can be read without declaring a distinct Value type for each.
+//
+// Example:
+//
+//	x := httpx.PathParam("x")
+//	if err := x.FromRequest(r); err != nil {
+//	    return err
+//	}
+//	id, err := x.Int()
+func PathParam(name string) *extractor.PathParamExtractor {
+	return extractor.NewPathParamExtractor(name)
+}
+
 // Additional type aliases for complete extractors that handle
 // collections of values rather than single named values.
 type (
@@ -83,6 +152,12 @@ type (
 
 	// Form provides access to all form values in a request
 	Form = extractor.FormExtractor
+
+	// RawBody provides the raw request body bytes while keeping the body readable by binders
+	RawBody = extractor.RawBodyExtractor
+
+	// NDJSON provides record-by-record access to a newline-delimited JSON request body
+	NDJSON[T any] = extractor.NDJSONExtractor[T]
 )
 
 // Empty is a no-op extractor that always succeeds without extracting any values.
@@ -2,12 +2,23 @@
 package httpx
 
 import (
+	"bytes"
 	"cmp"
+	"context"
+	"encoding/json"
 	"encoding/xml"
+	"errors"
+	"fmt"
 	"html/template"
 	"io"
+	"io/fs"
+	"mime"
 	"net/http"
-	
+	"path"
+	"reflect"
+	"strconv"
+	"time"
+
 	"github.com/eatmoreapple/hx/internal/serializer"
 )
 
@@ -17,34 +28,173 @@ type ResponseRender interface {
 	IntoResponse(http.ResponseWriter) error
 }
 
+// DefaultCharset is the charset text responses declare unless changed with SetDefaultCharset.
+const DefaultCharset = "utf-8"
+
+// defaultCharset is the charset parameter added to the content type of text responses.
+var defaultCharset = DefaultCharset
+
+// SetDefaultCharset sets the charset declared in the Content-Type of JSON, XML, string and HTML
+// responses, e.g. "application/json; charset=utf-8". An empty charset omits the parameter.
+// It should be called during initialization, before any response is rendered.
+func SetDefaultCharset(charset string) {
+	defaultCharset = charset
+}
+
+// textContentType returns mediaType with the default charset parameter, if any.
+func textContentType(mediaType string) string {
+	if defaultCharset == "" {
+		return mediaType
+	}
+	return mediaType + "; charset=" + defaultCharset
+}
+
 // JSONResponse represents a JSON response with data and status code.
 // It automatically sets the Content-Type header to application/json.
 type JSONResponse struct {
-	Data       any // Data to be encoded as JSON
-	StatusCode int // HTTP status code (defaults to 200 OK if not set)
+	Data       any  // Data to be encoded as JSON
+	StatusCode int  // HTTP status code (defaults to 200 OK if not set)
+	OmitNull   bool // OmitNull removes object properties whose value is null, at any depth
+
+	// View selects the audience the data is rendered for. Struct fields tagged with
+	// view:"admin,internal" are only included when View is one of the listed views,
+	// while fields without a view tag are always included. An empty View disables filtering.
+	// Filtered structs are encoded as objects, so their properties appear in sorted order.
+	View string
 }
 
 // IntoResponse implements ResponseRender for JSON responses.
 // It sets the appropriate content type, status code, and encodes the data as JSON.
 func (j JSONResponse) IntoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	data := j.Data
+	if j.View != "" {
+		data = applyView(reflect.ValueOf(data), j.View)
+	}
+	s := serializer.JSONSerializerFromWriter(w)
+	if j.OmitNull {
+		var buf bytes.Buffer
+		if err := s.Serialize(data, &buf); err != nil {
+			return err
+		}
+		stripped, err := stripNulls(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		w.Header().Set("Content-Type", textContentType("application/json"))
+		w.WriteHeader(cmp.Or(j.StatusCode, http.StatusOK))
+		_, err = w.Write(stripped)
+		return err
+	}
+	w.Header().Set("Content-Type", textContentType("application/json"))
 	w.WriteHeader(cmp.Or(j.StatusCode, http.StatusOK))
-	return serializer.JSONSerializer().Serialize(j.Data, w)
+	return s.Serialize(data, w)
+}
+
+// stripNulls rewrites the JSON document with null object properties removed, at any depth.
+// The document is rewritten token by token, so properties keep their order and numbers their precision.
+// Null array elements are preserved, as removing them would shift indices.
+func stripNulls(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := writeWithoutNulls(&buf, decoder, token); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes(), nil
+}
+
+// writeWithoutNulls writes the value starting with token to buf, reading the rest of it from decoder
+// and skipping object properties whose value is null.
+func writeWithoutNulls(buf *bytes.Buffer, decoder *json.Decoder, token json.Token) error {
+	switch t := token.(type) {
+	case json.Delim:
+		if t != '{' && t != '[' {
+			return fmt.Errorf("httpx: unexpected JSON delimiter %q", t)
+		}
+		buf.WriteRune(rune(t))
+		first := true
+		for decoder.More() {
+			var key json.Token
+			if t == '{' {
+				var err error
+				if key, err = decoder.Token(); err != nil {
+					return err
+				}
+			}
+			value, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if t == '{' && value == nil {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			if t == '{' {
+				if err := writeWithoutNulls(buf, decoder, key); err != nil {
+					return err
+				}
+				buf.WriteByte(':')
+			}
+			if err := writeWithoutNulls(buf, decoder, value); err != nil {
+				return err
+			}
+		}
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+		if t == '{' {
+			buf.WriteByte('}')
+		} else {
+			buf.WriteByte(']')
+		}
+	case string:
+		encoded, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		buf.Write(encoded)
+	case json.Number:
+		buf.WriteString(t.String())
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	case nil:
+		buf.WriteString("null")
+	}
+	return nil
 }
 
 // XMLResponse represents an XML response with data and status code.
 // It automatically sets the Content-Type header to application/xml.
 type XMLResponse struct {
-	Data       any // Data to be encoded as XML
-	StatusCode int // HTTP status code (defaults to 200 OK if not set)
+	Data        any    // Data to be encoded as XML
+	StatusCode  int    // HTTP status code (defaults to 200 OK if not set)
+	Declaration bool   // Declaration writes the <?xml ...?> header before the document
+	Indent      string // Indent is used to indent nested elements, compact output if empty
 }
 
 // IntoResponse implements ResponseRender for XML responses.
 // It sets the appropriate content type, status code, and encodes the data as XML.
 func (x XMLResponse) IntoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Type", textContentType("application/xml"))
 	w.WriteHeader(cmp.Or(x.StatusCode, http.StatusOK))
-	return xml.NewEncoder(w).Encode(x.Data)
+	if x.Declaration {
+		if _, err := io.WriteString(w, xml.Header); err != nil {
+			return err
+		}
+	}
+	encoder := xml.NewEncoder(w)
+	if x.Indent != "" {
+		encoder.Indent("", x.Indent)
+	}
+	return encoder.Encode(x.Data)
 }
 
 // StringResponse represents a plain text response with string data and status code.
@@ -57,7 +207,7 @@ type StringResponse struct {
 // IntoResponse implements ResponseRender for string responses.
 // It sets the appropriate content type, status code, and writes the string data.
 func (s StringResponse) IntoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Type", textContentType("text/plain"))
 	w.WriteHeader(cmp.Or(s.StatusCode, http.StatusOK))
 	_, err := io.WriteString(w, s.Data)
 	return err
@@ -74,7 +224,381 @@ type HTMLResponse struct {
 // IntoResponse implements ResponseRender for HTML responses.
 // It sets the appropriate content type, status code, and executes the template with the provided data.
 func (h HTMLResponse) IntoResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Type", textContentType("text/html"))
 	w.WriteHeader(cmp.Or(h.StatusCode, http.StatusOK))
 	return h.Template.Execute(w, h.Data)
 }
+
+// MarkdownRenderer converts Markdown source to HTML. It is implemented by adapters around a
+// Markdown library of choice, so httpx does not depend on any particular implementation.
+type MarkdownRenderer interface {
+	Convert(source []byte, w io.Writer) error
+}
+
+// MarkdownRendererFunc adapts a function into a MarkdownRenderer.
+type MarkdownRendererFunc func(source []byte, w io.Writer) error
+
+// Convert implements MarkdownRenderer.
+func (f MarkdownRendererFunc) Convert(source []byte, w io.Writer) error {
+	return f(source, w)
+}
+
+// MarkdownResponse represents Markdown source rendered to HTML by Renderer.
+// Without a Renderer the source is escaped and wrapped in <pre>, so the page stays readable.
+// The source is rendered before anything is written, so renderer errors leave the response untouched.
+//
+// Example:
+//
+//	md := goldmark.New()
+//	renderer := httpx.MarkdownRendererFunc(func(src []byte, w io.Writer) error { return md.Convert(src, w) })
+//	return httpx.MarkdownResponse{Source: page, Renderer: renderer}, nil
+type MarkdownResponse struct {
+	Source     string           // Source is the Markdown document
+	Renderer   MarkdownRenderer // Renderer converts the source, optional
+	StatusCode int              // HTTP status code (defaults to 200 OK if not set)
+}
+
+// IntoResponse implements ResponseRender for Markdown responses.
+func (m MarkdownResponse) IntoResponse(w http.ResponseWriter) error {
+	var body bytes.Buffer
+	if m.Renderer != nil {
+		if err := m.Renderer.Convert([]byte(m.Source), &body); err != nil {
+			return err
+		}
+	} else {
+		body.WriteString("<pre>")
+		template.HTMLEscape(&body, []byte(m.Source))
+		body.WriteString("</pre>")
+	}
+	w.Header().Set("Content-Type", textContentType("text/html"))
+	w.WriteHeader(cmp.Or(m.StatusCode, http.StatusOK))
+	_, err := body.WriteTo(w)
+	return err
+}
+
+// LayoutData is the data passed to the layout template of a LayoutResponse.
+type LayoutData struct {
+	Content template.HTML // Content is the rendered content template
+	Data    any           // Data is the data the content template was rendered with
+}
+
+// LayoutResponse represents an HTML response composed of a content template rendered inside a layout.
+// Both templates are looked up by name in Template. The content template is executed with Data first,
+// and the layout is then executed with a LayoutData, so it injects the content with {{ .Content }}
+// and can still reach the page data through {{ .Data }}.
+//
+// Example layout:
+//
+//	{{ define "layout" }}<html><title>{{ .Data.Title }}</title><body>{{ .Content }}</body></html>{{ end }}
+type LayoutResponse struct {
+	Data       any                // Data to be passed to the content template
+	StatusCode int                // HTTP status code (defaults to 200 OK if not set)
+	Template   *template.Template // Template set containing both the layout and content templates
+	Layout     string             // Name of the layout template
+	Content    string             // Name of the content template
+}
+
+// IntoResponse implements ResponseRender for layout responses.
+// The content is rendered before anything is written, so content errors leave the response untouched.
+func (l LayoutResponse) IntoResponse(w http.ResponseWriter) error {
+	var content bytes.Buffer
+	if err := l.Template.ExecuteTemplate(&content, l.Content, l.Data); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", textContentType("text/html"))
+	w.WriteHeader(cmp.Or(l.StatusCode, http.StatusOK))
+	return l.Template.ExecuteTemplate(w, l.Layout, LayoutData{
+		Content: template.HTML(content.String()),
+		Data:    l.Data,
+	})
+}
+
+// TeeResponse wraps another ResponseRender and duplicates the rendered body into Sink.
+// It is useful for logging or caching responses while still streaming them to the client.
+// Headers and status code are written to the client only.
+type TeeResponse struct {
+	Render ResponseRender // Render produces the response
+	Sink   io.Writer      // Sink receives a copy of every byte written to the response body
+}
+
+// IntoResponse implements ResponseRender for tee responses.
+// It renders the wrapped response through a writer that copies the body into Sink.
+func (t TeeResponse) IntoResponse(w http.ResponseWriter) error {
+	return t.Render.IntoResponse(&teeResponseWriter{
+		ResponseWriter: w,
+		writer:         io.MultiWriter(w, t.Sink),
+	})
+}
+
+// teeResponseWriter is an http.ResponseWriter that sends body writes to multiple destinations.
+type teeResponseWriter struct {
+	http.ResponseWriter
+	writer io.Writer
+}
+
+// Write writes b to both the underlying ResponseWriter and the sink.
+func (t *teeResponseWriter) Write(b []byte) (int, error) {
+	return t.writer.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (t *teeResponseWriter) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
+
+// RedirectResponse represents a redirect to another URL.
+// It sets the Location header and writes a redirect status code.
+type RedirectResponse struct {
+	URL        string // URL to redirect to
+	StatusCode int    // HTTP status code (defaults to 302 Found if not set)
+}
+
+// IntoResponse implements ResponseRender for redirect responses.
+// It sets the Location header and the status code without writing a body.
+func (r RedirectResponse) IntoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Location", r.URL)
+	w.WriteHeader(cmp.Or(r.StatusCode, http.StatusFound))
+	return nil
+}
+
+// NoContentResponse represents an empty 204 No Content response.
+type NoContentResponse struct{}
+
+// IntoResponse implements ResponseRender for empty responses.
+func (NoContentResponse) IntoResponse(w http.ResponseWriter) error {
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// NDJSONResponse represents a newline-delimited JSON response, written one item per line
+// with the Content-Type application/x-ndjson. Items are taken from Items, then from Stream
+// until it is closed or Context is done, so a response can either export a fixed set of records
+// or stream them as they are produced. The response is flushed every FlushEvery items.
+//
+// Example:
+//
+//	return httpx.NDJSONResponse[Event]{Context: ctx, Stream: events}, nil
+type NDJSONResponse[T any] struct {
+	Items      []T             // Items to write first
+	Stream     <-chan T        // Stream of items written after Items, until the channel is closed
+	Context    context.Context // Context stops reading Stream when done, typically the request context
+	StatusCode int             // HTTP status code (defaults to 200 OK if not set)
+	FlushEvery int             // FlushEvery is the number of items written between flushes (defaults to 1)
+}
+
+// IntoResponse implements ResponseRender for NDJSON responses.
+// Each item is encoded with the configured JSON serializer and compacted onto a single line.
+func (n NDJSONResponse[T]) IntoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(cmp.Or(n.StatusCode, http.StatusOK))
+
+	controller := http.NewResponseController(w)
+	flushEvery := max(n.FlushEvery, 1)
+	written := 0
+
+	s := serializer.JSONSerializerFromWriter(w)
+	var line, encoded bytes.Buffer
+	write := func(item T) error {
+		encoded.Reset()
+		line.Reset()
+		if err := s.Serialize(item, &encoded); err != nil {
+			return err
+		}
+		if err := json.Compact(&line, encoded.Bytes()); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		if _, err := w.Write(line.Bytes()); err != nil {
+			return err
+		}
+		if written++; written%flushEvery == 0 {
+			// flushing is best effort, the writer may not support it
+			_ = controller.Flush()
+		}
+		return nil
+	}
+
+	for _, item := range n.Items {
+		if err := write(item); err != nil {
+			return err
+		}
+	}
+
+	if n.Stream != nil {
+		ctx := n.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+	loop:
+		for {
+			select {
+			case <-ctx.Done():
+				break loop
+			case item, ok := <-n.Stream:
+				if !ok {
+					break loop
+				}
+				if err := write(item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if written%flushEvery != 0 {
+		_ = controller.Flush()
+	}
+	return nil
+}
+
+// Result renders either a value or an error in a uniform JSON envelope:
+//
+//	{"data": {...}, "error": null}      on success, with 200 OK
+//	{"data": null, "error": "message"}  on error, with 500 Internal Server Error
+//
+// The error status can be chosen by an error implementing StatusCode() int, found with errors.As,
+// and StatusCode overrides the status in both cases.
+type Result[T any] struct {
+	Data       T     // Data is rendered when Err is nil
+	Err        error // Err is rendered by its message when not nil
+	StatusCode int   // StatusCode overrides the status code of the response
+}
+
+// resultEnvelope is the JSON shape rendered by Result.
+type resultEnvelope struct {
+	Data  any     `json:"data"`
+	Error *string `json:"error"`
+}
+
+// IntoResponse implements ResponseRender for result envelopes.
+func (r Result[T]) IntoResponse(w http.ResponseWriter) error {
+	if r.Err == nil {
+		return JSONResponse{Data: resultEnvelope{Data: r.Data}, StatusCode: r.StatusCode}.IntoResponse(w)
+	}
+	status := http.StatusInternalServerError
+	var coder interface{ StatusCode() int }
+	if errors.As(r.Err, &coder) {
+		status = coder.StatusCode()
+	}
+	message := r.Err.Error()
+	return JSONResponse{
+		Data:       resultEnvelope{Error: &message},
+		StatusCode: cmp.Or(r.StatusCode, status),
+	}.IntoResponse(w)
+}
+
+// PaginatedResponse represents one page of a list, rendered as JSON in the envelope
+//
+//	{"items": [...], "total": 42, "page": 2, "size": 20}
+//
+// where total counts the items of every page. A nil Items slice is rendered as an empty list.
+type PaginatedResponse[T any] struct {
+	Items      []T // Items of the page
+	Total      int // Total number of items across all pages
+	Page       int // Page is the 1-based page number
+	Size       int // Size is the maximum number of items per page
+	StatusCode int // HTTP status code (defaults to 200 OK if not set)
+}
+
+// paginatedEnvelope is the JSON shape rendered by PaginatedResponse.
+type paginatedEnvelope[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+	Page  int `json:"page"`
+	Size  int `json:"size"`
+}
+
+// IntoResponse implements ResponseRender for paginated responses.
+func (p PaginatedResponse[T]) IntoResponse(w http.ResponseWriter) error {
+	items := p.Items
+	if items == nil {
+		items = []T{}
+	}
+	return JSONResponse{
+		Data:       paginatedEnvelope[T]{Items: items, Total: p.Total, Page: p.Page, Size: p.Size},
+		StatusCode: p.StatusCode,
+	}.IntoResponse(w)
+}
+
+// RangeResponse represents generated content served with support for range and conditional
+// requests through http.ServeContent: a request with "Range: bytes=0-1023" receives
+// 206 Partial Content with a Content-Range header, and unsatisfiable ranges yield 416.
+// Without Request the full content is written.
+//
+// Example:
+//
+//	return httpx.RangeResponse{Content: bytes.NewReader(archive), ContentType: "application/zip", Request: r}, nil
+type RangeResponse struct {
+	Content     io.ReadSeeker // Content is the response body, seeked to serve ranges
+	ContentType string        // ContentType of the content, sniffed from the data when empty
+	ModTime     time.Time     // ModTime enables Last-Modified and If-Modified-Since handling when non-zero
+	Request     *http.Request // Request carries the Range and conditional headers, optional
+}
+
+// IntoResponse implements ResponseRender for range responses.
+func (rr RangeResponse) IntoResponse(w http.ResponseWriter) error {
+	req := rr.Request
+	if req == nil {
+		req = &http.Request{Method: http.MethodGet, Header: http.Header{}}
+	}
+	if rr.ContentType != "" {
+		w.Header().Set("Content-Type", rr.ContentType)
+	}
+	http.ServeContent(w, req, "", rr.ModTime, rr.Content)
+	return nil
+}
+
+// FSFileResponse represents a file served from an fs.FS, typically after the handler has
+// checked that the caller may access it. The content type is derived from the file extension.
+// When Request is set, conditional and range requests are honored through http.ServeContent,
+// so large files can be resumed and seeked. A missing file yields a plain 404 Not Found.
+//
+// Example:
+//
+//	return httpx.FSFileResponse{FS: uploads, Path: "reports/" + id + ".pdf", Request: r}, nil
+type FSFileResponse struct {
+	FS      fs.FS         // FS holds the file
+	Path    string        // Path of the file within FS, in fs.ValidPath form
+	Request *http.Request // Request enables conditional and range handling, optional
+}
+
+// IntoResponse implements ResponseRender for file responses.
+func (f FSFileResponse) IntoResponse(w http.ResponseWriter) error {
+	file, err := f.FS.Open(f.Path)
+	if errors.Is(err, fs.ErrNotExist) || errors.Is(err, fs.ErrInvalid) {
+		http.NotFound(w, f.Request)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		http.NotFound(w, f.Request)
+		return nil
+	}
+
+	content, ok := file.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return err
+		}
+		content = bytes.NewReader(data)
+	}
+
+	req := f.Request
+	if req == nil {
+		req = &http.Request{Method: http.MethodGet, Header: http.Header{}}
+	}
+	if contentType := mime.TypeByExtension(path.Ext(f.Path)); contentType != "" {
+		w.Header().Set("Content-Type", contentType)
+	}
+	http.ServeContent(w, req, path.Base(f.Path), info.ModTime(), content)
+	return nil
+}
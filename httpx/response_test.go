@@ -0,0 +1,416 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestTeeResponse(t *testing.T) {
+	var sink bytes.Buffer
+	w := httptest.NewRecorder()
+
+	render := TeeResponse{
+		Render: JSONResponse{Data: map[string]string{"message": "hello"}, StatusCode: http.StatusCreated},
+		Sink:   &sink,
+	}
+	if err := render.IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, w.Code)
+	}
+
+	if w.Body.String() != sink.String() {
+		t.Errorf("expected sink %q to equal body %q", sink.String(), w.Body.String())
+	}
+
+	if sink.String() != "{\"message\":\"hello\"}\n" {
+		t.Errorf("unexpected body %q", sink.String())
+	}
+}
+
+func TestXMLResponseDeclaration(t *testing.T) {
+	type Item struct {
+		Name string `xml:"name"`
+	}
+
+	w := httptest.NewRecorder()
+	if err := (XMLResponse{Data: Item{Name: "gopher"}, Declaration: true}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := xml.Header + "<Item><name>gopher</name></Item>"
+	if w.Body.String() != expected {
+		t.Errorf("expected body %q, got %q", expected, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if err := (XMLResponse{Data: Item{Name: "gopher"}}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.HasPrefix(w.Body.String(), "<?xml") {
+		t.Errorf("expected no declaration by default, got %q", w.Body.String())
+	}
+}
+
+func TestXMLResponseIndent(t *testing.T) {
+	type Item struct {
+		Name string   `xml:"name"`
+		Tags []string `xml:"tags>tag"`
+	}
+
+	w := httptest.NewRecorder()
+	if err := (XMLResponse{Data: Item{Name: "gopher", Tags: []string{"a"}}, Indent: "  "}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "<Item>\n  <name>gopher</name>\n  <tags>\n    <tag>a</tag>\n  </tags>\n</Item>"
+	if w.Body.String() != expected {
+		t.Errorf("expected body %q, got %q", expected, w.Body.String())
+	}
+}
+
+func TestLayoutResponse(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(
+		`{{ define "layout" }}<title>{{ .Data.Title }}</title><main>{{ .Content }}</main>{{ end }}` +
+			`{{ define "page" }}<h1>{{ .Title }}</h1><p>{{ .Body }}</p>{{ end }}`,
+	))
+
+	w := httptest.NewRecorder()
+	render := LayoutResponse{
+		Data:     map[string]string{"Title": "Home", "Body": "<hi>"},
+		Template: tmpl,
+		Layout:   "layout",
+		Content:  "page",
+	}
+	if err := render.IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "<title>Home</title><main><h1>Home</h1><p>&lt;hi&gt;</p></main>"
+	if w.Body.String() != expected {
+		t.Errorf("expected body %q, got %q", expected, w.Body.String())
+	}
+
+	if w.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Errorf("expected html content type, got %s", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestJSONResponseView(t *testing.T) {
+	type Account struct {
+		Name  string `json:"name"`
+		Email string `json:"email" view:"admin"`
+		Notes string `json:"notes,omitempty" view:"admin,support"`
+	}
+	type Page struct {
+		Accounts []Account `json:"accounts"`
+	}
+	page := Page{Accounts: []Account{{Name: "gopher", Email: "gopher@example.com", Notes: "vip"}}}
+
+	tests := []struct {
+		view     string
+		expected string
+	}{
+		{"public", "{\"accounts\":[{\"name\":\"gopher\"}]}\n"},
+		{"admin", "{\"accounts\":[{\"email\":\"gopher@example.com\",\"name\":\"gopher\",\"notes\":\"vip\"}]}\n"},
+		{"support", "{\"accounts\":[{\"name\":\"gopher\",\"notes\":\"vip\"}]}\n"},
+		{"", "{\"accounts\":[{\"name\":\"gopher\",\"email\":\"gopher@example.com\",\"notes\":\"vip\"}]}\n"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		if err := (JSONResponse{Data: page, View: tt.view}).IntoResponse(w); err != nil {
+			t.Fatalf("view %q: unexpected error: %v", tt.view, err)
+		}
+
+		if w.Body.String() != tt.expected {
+			t.Errorf("view %q: expected body %q, got %q", tt.view, tt.expected, w.Body.String())
+		}
+	}
+}
+
+// upperName marshals with a pointer receiver, so encoding/json only uses it for addressable values.
+type upperName string
+
+func (u *upperName) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(string(*u)))
+}
+
+func TestJSONResponseViewMatchesEncodingJSON(t *testing.T) {
+	type Event struct {
+		Name     upperName `json:"name"`
+		ID       int64     `json:"id,string"`
+		Ref      *int      `json:"ref,string"`
+		At       time.Time `json:"at,omitempty"`
+		Count    int       `json:"count,omitempty"`
+		Internal string    `json:"internal" view:"admin"`
+	}
+	event := &Event{Name: "launch", ID: 42}
+
+	w := httptest.NewRecorder()
+	if err := (JSONResponse{Data: event, View: "public"}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got, expected map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	encoded, _ := json.Marshal(event)
+	_ = json.Unmarshal(encoded, &expected)
+	delete(expected, "internal")
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %v, got %v", expected, got)
+	}
+
+	if got["at"] != "0001-01-01T00:00:00Z" || got["id"] != "42" || got["name"] != "LAUNCH" {
+		t.Errorf("expected the zero time, the quoted id and the marshaled name, got %v", got)
+	}
+}
+
+func TestJSONResponseOmitNull(t *testing.T) {
+	type Profile struct {
+		Bio *string `json:"bio"`
+	}
+	type User struct {
+		Name    string   `json:"name"`
+		Avatar  *string  `json:"avatar"`
+		Profile Profile  `json:"profile"`
+		Tags    []string `json:"tags"`
+		ID      int64    `json:"id"`
+	}
+	user := User{Name: "gopher", ID: 1234567890123456789}
+
+	w := httptest.NewRecorder()
+	if err := (JSONResponse{Data: user, OmitNull: true}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "{\"name\":\"gopher\",\"profile\":{},\"id\":1234567890123456789}\n"
+	if w.Body.String() != expected {
+		t.Errorf("expected body %q, got %q", expected, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if err := (JSONResponse{Data: user}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(w.Body.String(), "\"avatar\":null") {
+		t.Errorf("expected nulls to be kept by default, got %q", w.Body.String())
+	}
+}
+
+func TestNDJSONResponse(t *testing.T) {
+	type Event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	stream := make(chan Event, 2)
+	stream <- Event{ID: 2, Name: "second"}
+	stream <- Event{ID: 3, Name: "third"}
+	close(stream)
+
+	w := httptest.NewRecorder()
+	response := NDJSONResponse[Event]{Items: []Event{{ID: 1, Name: "first"}}, Stream: stream}
+	if err := response.IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Header().Get("Content-Type") != "application/x-ndjson" {
+		t.Errorf("expected ndjson content type, got %s", w.Header().Get("Content-Type"))
+	}
+
+	if !w.Flushed {
+		t.Error("expected response to be flushed")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d: %q", len(lines), w.Body.String())
+	}
+
+	for i, line := range lines {
+		var event Event
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Errorf("line %d is not valid JSON: %v", i, err)
+		}
+		if event.ID != i+1 {
+			t.Errorf("line %d: expected id %d, got %d", i, i+1, event.ID)
+		}
+	}
+}
+
+func TestFSFileResponse(t *testing.T) {
+	fsys := fstest.MapFS{"docs/report.txt": {Data: []byte("0123456789")}}
+
+	w := httptest.NewRecorder()
+	if err := (FSFileResponse{FS: fsys, Path: "docs/report.txt"}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusOK || w.Body.String() != "0123456789" {
+		t.Errorf("expected full file, got %d %q", w.Code, w.Body.String())
+	}
+
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Errorf("expected text/plain content type, got %s", w.Header().Get("Content-Type"))
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/download", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	w = httptest.NewRecorder()
+	if err := (FSFileResponse{FS: fsys, Path: "docs/report.txt", Request: req}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusPartialContent || w.Body.String() != "234" {
+		t.Errorf("expected partial content, got %d %q", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if err := (FSFileResponse{FS: fsys, Path: "docs/missing.txt"}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestRangeResponse(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/export", nil)
+	req.Header.Set("Range", "bytes=3-6")
+
+	w := httptest.NewRecorder()
+	render := RangeResponse{Content: strings.NewReader("0123456789"), ContentType: "text/csv", Request: req}
+	if err := render.IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusPartialContent {
+		t.Errorf("expected status code %d, got %d", http.StatusPartialContent, w.Code)
+	}
+
+	if w.Header().Get("Content-Range") != "bytes 3-6/10" {
+		t.Errorf("expected Content-Range bytes 3-6/10, got %s", w.Header().Get("Content-Range"))
+	}
+
+	if w.Body.String() != "3456" || w.Header().Get("Content-Type") != "text/csv" {
+		t.Errorf("expected partial text/csv body, got %q %s", w.Body.String(), w.Header().Get("Content-Type"))
+	}
+}
+
+type conflictError struct{}
+
+func (conflictError) Error() string   { return "already exists" }
+func (conflictError) StatusCode() int { return http.StatusConflict }
+
+func TestResult(t *testing.T) {
+	w := httptest.NewRecorder()
+	if err := (Result[map[string]int]{Data: map[string]int{"id": 1}}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != `{"data":{"id":1},"error":null}` {
+		t.Errorf("unexpected success envelope %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if err := (Result[map[string]int]{Err: conflictError{}}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Code != http.StatusConflict || strings.TrimSpace(w.Body.String()) != `{"data":null,"error":"already exists"}` {
+		t.Errorf("unexpected error envelope %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestSetDefaultCharset(t *testing.T) {
+	defer SetDefaultCharset(DefaultCharset)
+
+	render := func() string {
+		w := httptest.NewRecorder()
+		if err := (JSONResponse{Data: "ok"}).IntoResponse(w); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return w.Header().Get("Content-Type")
+	}
+
+	SetDefaultCharset("")
+	if contentType := render(); contentType != "application/json" {
+		t.Errorf("expected no charset, got %s", contentType)
+	}
+
+	SetDefaultCharset("utf-8")
+	if contentType := render(); contentType != "application/json; charset=utf-8" {
+		t.Errorf("expected utf-8 charset, got %s", contentType)
+	}
+}
+
+func TestMarkdownResponse(t *testing.T) {
+	renderer := MarkdownRendererFunc(func(source []byte, w io.Writer) error {
+		title, ok := strings.CutPrefix(string(source), "# ")
+		if !ok {
+			return errors.New("unsupported markdown")
+		}
+		_, err := io.WriteString(w, "<h1>"+title+"</h1>")
+		return err
+	})
+
+	w := httptest.NewRecorder()
+	if err := (MarkdownResponse{Source: "# Title", Renderer: renderer}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Body.String() != "<h1>Title</h1>" || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Errorf("expected rendered HTML, got %q %s", w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	if err := (MarkdownResponse{Source: "# <Title>"}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if w.Body.String() != "<pre># &lt;Title&gt;</pre>" {
+		t.Errorf("expected escaped source without a renderer, got %q", w.Body.String())
+	}
+}
+
+func TestPaginatedResponse(t *testing.T) {
+	w := httptest.NewRecorder()
+	render := PaginatedResponse[string]{Items: []string{"a", "b"}, Total: 5, Page: 1, Size: 2}
+	if err := render.IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := `{"items":["a","b"],"total":5,"page":1,"size":2}`
+	if strings.TrimSpace(w.Body.String()) != expected {
+		t.Errorf("expected %s, got %s", expected, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	if err := (PaginatedResponse[string]{Total: 5, Page: 3, Size: 2}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !strings.Contains(w.Body.String(), `"items":[]`) {
+		t.Errorf("expected nil items to render as an empty list, got %s", w.Body.String())
+	}
+}
@@ -0,0 +1,117 @@
+package httpx
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SSEEvent is a single server-sent event.
+// Line breaks in ID and Event are removed, so they cannot start fields of their own.
+type SSEEvent struct {
+	ID    string // ID sets the event ID, omitted if empty
+	Event string // Event sets the event type, omitted if empty
+	Data  string // Data is the event payload, split into one data line per line of text
+	Retry int    // Retry sets the client reconnection time in milliseconds, omitted if zero
+}
+
+// sseLineBreaks removes the line breaks of single-line SSE fields.
+var sseLineBreaks = strings.NewReplacer("\r", "", "\n", "")
+
+// sseNewlines normalizes the line breaks of SSE data, which clients also split on CR.
+var sseNewlines = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// writeTo writes the event in the text/event-stream format.
+func (e SSEEvent) writeTo(w io.Writer) error {
+	var sb strings.Builder
+	if id := sseLineBreaks.Replace(e.ID); id != "" {
+		fmt.Fprintf(&sb, "id: %s\n", id)
+	}
+	if event := sseLineBreaks.Replace(e.Event); event != "" {
+		fmt.Fprintf(&sb, "event: %s\n", event)
+	}
+	if e.Retry > 0 {
+		fmt.Fprintf(&sb, "retry: %d\n", e.Retry)
+	}
+	for _, line := range strings.Split(sseNewlines.Replace(e.Data), "\n") {
+		fmt.Fprintf(&sb, "data: %s\n", line)
+	}
+	sb.WriteString("\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// SSEResponse represents a server-sent events stream.
+// Events received from the Events channel are written and flushed as they arrive until the
+// channel is closed or Context is done. When KeepAlive is set, a comment line is written
+// whenever no event was sent for that long, so proxies do not drop idle connections.
+//
+// Example:
+//
+//	return httpx.SSEResponse{Context: ctx, Events: events, KeepAlive: 15 * time.Second}, nil
+type SSEResponse struct {
+	Context   context.Context // Context stops the stream when done, typically the request context
+	Events    <-chan SSEEvent // Events to send, the stream ends when the channel is closed
+	KeepAlive time.Duration   // KeepAlive is the idle interval between keepalive comments, disabled if zero
+}
+
+// sseKeepAlive is the comment written to keep idle connections open.
+const sseKeepAlive = ": keepalive\n\n"
+
+// IntoResponse implements ResponseRender for server-sent events.
+// It streams events until the channel is closed or the context is done.
+func (s SSEResponse) IntoResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	if err := controller.Flush(); err != nil {
+		return err
+	}
+
+	ctx := s.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	// a nil channel never fires, which disables keepalive
+	var (
+		ticker    *time.Ticker
+		keepAlive <-chan time.Time
+	)
+	if s.KeepAlive > 0 {
+		ticker = time.NewTicker(s.KeepAlive)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-s.Events:
+			if !ok {
+				return nil
+			}
+			if err := event.writeTo(w); err != nil {
+				return err
+			}
+			// the stream is not idle, so the next keepalive is due an interval after this event
+			if ticker != nil {
+				ticker.Reset(s.KeepAlive)
+			}
+		case <-keepAlive:
+			if _, err := io.WriteString(w, sseKeepAlive); err != nil {
+				return err
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package httpx
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// flushRecorder is a ResponseRecorder safe for concurrent inspection that counts flushes.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	mu      sync.Mutex
+	flushes int
+}
+
+func (f *flushRecorder) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.ResponseRecorder.Write(b)
+}
+
+func (f *flushRecorder) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *flushRecorder) Flush() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flushes++
+}
+
+func (f *flushRecorder) body() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.Body.String()
+}
+
+func TestSSEResponse(t *testing.T) {
+	events := make(chan SSEEvent, 2)
+	events <- SSEEvent{ID: "1", Event: "greeting", Data: "hello\nworld"}
+	close(events)
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	if err := (SSEResponse{Events: events}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "id: 1\nevent: greeting\ndata: hello\ndata: world\n\n"
+	if w.body() != expected {
+		t.Errorf("expected body %q, got %q", expected, w.body())
+	}
+
+	if w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("expected event stream content type, got %s", w.Header().Get("Content-Type"))
+	}
+}
+
+func TestSSEResponseLineBreaks(t *testing.T) {
+	events := make(chan SSEEvent, 1)
+	events <- SSEEvent{ID: "1\r\nevent: admin", Event: "greeting\ndata: forged", Data: "hello\r\nworld\rbye"}
+	close(events)
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	if err := (SSEResponse{Events: events}).IntoResponse(w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "id: 1event: admin\nevent: greetingdata: forged\ndata: hello\ndata: world\ndata: bye\n\n"
+	if w.body() != expected {
+		t.Errorf("expected body %q, got %q", expected, w.body())
+	}
+}
+
+func TestSSEResponseKeepAlive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan SSEEvent)
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	done := make(chan error)
+	go func() {
+		done <- SSEResponse{Context: ctx, Events: events, KeepAlive: 10 * time.Millisecond}.IntoResponse(w)
+	}()
+
+	deadline := time.After(time.Second)
+	for !strings.Contains(w.body(), sseKeepAlive) {
+		select {
+		case <-deadline:
+			t.Fatal("expected a keepalive comment")
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestSSEResponseKeepAliveBusyStream(t *testing.T) {
+	events := make(chan SSEEvent)
+
+	w := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	done := make(chan error)
+	go func() {
+		done <- SSEResponse{Events: events, KeepAlive: 100 * time.Millisecond}.IntoResponse(w)
+	}()
+
+	// events arrive much faster than the keepalive interval for several intervals
+	for deadline := time.Now().Add(400 * time.Millisecond); time.Now().Before(deadline); {
+		events <- SSEEvent{Data: "tick"}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(events)
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if strings.Contains(w.body(), sseKeepAlive) {
+		t.Errorf("expected no keepalive on a busy stream")
+	}
+}
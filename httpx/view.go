@@ -0,0 +1,206 @@
+package httpx
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var (
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// applyView converts v into generic JSON values, omitting struct fields whose "view" tag
+// does not list view. Fields without a view tag are always kept. The "json" tag is honored
+// for field names, "-", omitempty, omitzero and string, following the encoding/json rules.
+// Values implementing json.Marshaler or encoding.TextMarshaler, including through a pointer receiver
+// when addressable, are kept as is so they encode exactly as before.
+func applyView(v reflect.Value, view string) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if render, ok := asMarshaler(v); ok {
+		return render
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return applyView(v.Elem(), view)
+	case reflect.Struct:
+		fields := make(map[string]any, v.NumField())
+		viewStruct(v, view, fields)
+		return fields
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 { // []byte encodes as base64
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		elems := make([]any, v.Len())
+		for i := range elems {
+			elems[i] = applyView(v.Index(i), view)
+		}
+		return elems
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return v.Interface()
+		}
+		entries := make(map[string]any, v.Len())
+		for iter := v.MapRange(); iter.Next(); {
+			entries[iter.Key().String()] = applyView(iter.Value(), view)
+		}
+		return entries
+	default:
+		return v.Interface()
+	}
+}
+
+// viewStruct adds the fields of struct v visible in view to fields.
+// Untagged embedded structs are flattened like encoding/json does.
+func viewStruct(v reflect.Value, view string, fields map[string]any) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		if tag, ok := f.Tag.Lookup("view"); ok && !slices.Contains(strings.Split(tag, ","), view) {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		field := v.Field(i)
+		if f.Anonymous && name == "" {
+			if field.Kind() == reflect.Pointer {
+				if field.IsNil() {
+					continue
+				}
+				field = field.Elem()
+			}
+			if field.Kind() == reflect.Struct {
+				viewStruct(field, view, fields)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		options := strings.Split(opts, ",")
+		if slices.Contains(options, "omitempty") && isEmptyValue(field) {
+			continue
+		}
+		if slices.Contains(options, "omitzero") && isZeroValue(field) {
+			continue
+		}
+		if slices.Contains(options, "string") {
+			if quoted, ok := quoteValue(field); ok {
+				fields[cmp.Or(name, f.Name)] = quoted
+				continue
+			}
+		}
+		fields[cmp.Or(name, f.Name)] = applyView(field, view)
+	}
+}
+
+// asMarshaler returns v as a value encoding/json would marshal with MarshalJSON or MarshalText,
+// checking the pointer receiver methods when v is addressable.
+func asMarshaler(v reflect.Value) (any, bool) {
+	if v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType) {
+		return v.Interface(), true
+	}
+	if v.Kind() != reflect.Pointer && v.CanAddr() {
+		pointerType := reflect.PointerTo(v.Type())
+		if pointerType.Implements(jsonMarshalerType) || pointerType.Implements(textMarshalerType) {
+			return v.Addr().Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// quoteValue returns the JSON encoding of v as a string, as the json string option does for
+// string, floating point, integer and boolean fields, or pointers to them. A nil pointer stays null.
+// It reports false for other kinds and for values with their own marshaler, which ignore the option.
+func quoteValue(v reflect.Value) (any, bool) {
+	if _, ok := asMarshaler(v); ok {
+		return nil, false
+	}
+	if v.Kind() == reflect.Pointer {
+		if !isQuotableKind(v.Type().Elem().Kind()) {
+			return nil, false
+		}
+		if v.IsNil() {
+			return nil, true
+		}
+		v = v.Elem()
+	}
+	if !isQuotableKind(v.Kind()) {
+		return nil, false
+	}
+	encoded, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, false
+	}
+	return string(encoded), true
+}
+
+// isQuotableKind reports whether the json string option applies to values of kind k.
+func isQuotableKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// isEmptyValue reports whether v is empty in the sense of the json omitempty option.
+// Structs are never empty, so a zero time.Time is still encoded.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	default:
+		return false
+	}
+}
+
+// isZeroValue reports whether v is zero in the sense of the json omitzero option,
+// using its IsZero method when it has one.
+func isZeroValue(v reflect.Value) bool {
+	if zeroer, ok := v.Interface().(interface{ IsZero() bool }); ok {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return true
+		}
+		return zeroer.IsZero()
+	}
+	if v.CanAddr() {
+		if zeroer, ok := v.Addr().Interface().(interface{ IsZero() bool }); ok {
+			return zeroer.IsZero()
+		}
+	}
+	return v.IsZero()
+}
@@ -0,0 +1,159 @@
+package hxtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+)
+
+// TestClient sends requests to an http.Handler, typically an *hx.Router, without a network.
+// Headers and cookies set on the client are sent with every request.
+type TestClient struct {
+	handler http.Handler
+	header  http.Header
+	cookies []*http.Cookie
+}
+
+// Client creates a TestClient that exercises the given handler, usually an *hx.Router.
+//
+// Example:
+//
+//	client := hxtest.Client(router)
+//	var user User
+//	resp, err := client.GET("/users/1").JSON(&user)
+func Client(handler http.Handler) *TestClient {
+	return &TestClient{handler: handler, header: make(http.Header)}
+}
+
+// Header sets a header sent with every request made by the client.
+func (c *TestClient) Header(key, value string) *TestClient {
+	c.header.Set(key, value)
+	return c
+}
+
+// Cookie adds a cookie sent with every request made by the client.
+func (c *TestClient) Cookie(cookie *http.Cookie) *TestClient {
+	c.cookies = append(c.cookies, cookie)
+	return c
+}
+
+// Request starts building a request with the given method and path.
+func (c *TestClient) Request(method, path string) *Request {
+	return &Request{client: c, method: method, path: path, header: c.header.Clone()}
+}
+
+// GET starts building a GET request.
+func (c *TestClient) GET(path string) *Request { return c.Request(http.MethodGet, path) }
+
+// POST starts building a POST request.
+func (c *TestClient) POST(path string) *Request { return c.Request(http.MethodPost, path) }
+
+// PUT starts building a PUT request.
+func (c *TestClient) PUT(path string) *Request { return c.Request(http.MethodPut, path) }
+
+// PATCH starts building a PATCH request.
+func (c *TestClient) PATCH(path string) *Request { return c.Request(http.MethodPatch, path) }
+
+// DELETE starts building a DELETE request.
+func (c *TestClient) DELETE(path string) *Request { return c.Request(http.MethodDelete, path) }
+
+// Request is a request being built by a TestClient.
+type Request struct {
+	client  *TestClient
+	method  string
+	path    string
+	header  http.Header
+	cookies []*http.Cookie
+	body    io.Reader
+	err     error
+}
+
+// Header sets a header on the request.
+func (r *Request) Header(key, value string) *Request {
+	r.header.Set(key, value)
+	return r
+}
+
+// Cookie adds a cookie to the request.
+func (r *Request) Cookie(cookie *http.Cookie) *Request {
+	r.cookies = append(r.cookies, cookie)
+	return r
+}
+
+// Body encodes v as JSON and uses it as the request body.
+// The Content-Type header defaults to application/json.
+func (r *Request) Body(v any) *Request {
+	data, err := json.Marshal(v)
+	if err != nil {
+		r.err = fmt.Errorf("hxtest: encoding body: %w", err)
+		return r
+	}
+	return r.RawBody(bytes.NewReader(data), "application/json")
+}
+
+// RawBody uses body as the request body with the given Content-Type.
+func (r *Request) RawBody(body io.Reader, contentType string) *Request {
+	r.body = body
+	if r.header.Get("Content-Type") == "" {
+		r.header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+// Do sends the request to the handler and returns the recorded response.
+func (r *Request) Do() (*Response, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	req := httptest.NewRequest(r.method, r.path, r.body)
+	req.Header = r.header
+	for _, cookie := range slices.Concat(r.client.cookies, r.cookies) {
+		req.AddCookie(cookie)
+	}
+
+	recorder := httptest.NewRecorder()
+	r.client.handler.ServeHTTP(recorder, req)
+
+	result := recorder.Result()
+	return &Response{
+		StatusCode: result.StatusCode,
+		Header:     result.Header,
+		Cookies:    result.Cookies(),
+		Body:       recorder.Body.Bytes(),
+	}, nil
+}
+
+// JSON sends the request and decodes the JSON response body into out.
+// The response is returned even if decoding fails, so the status code can be inspected.
+func (r *Request) JSON(out any) (*Response, error) {
+	resp, err := r.Do()
+	if err != nil {
+		return nil, err
+	}
+	return resp, resp.JSON(out)
+}
+
+// Response is a response recorded by a TestClient.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Cookies    []*http.Cookie
+	Body       []byte
+}
+
+// JSON decodes the response body into out.
+func (r *Response) JSON(out any) error {
+	if err := json.Unmarshal(r.Body, out); err != nil {
+		return fmt.Errorf("hxtest: decoding response (status %d): %w", r.StatusCode, err)
+	}
+	return nil
+}
+
+// String returns the response body as a string.
+func (r *Response) String() string {
+	return string(r.Body)
+}
@@ -0,0 +1,99 @@
+package hxtest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/eatmoreapple/hx"
+)
+
+type user struct {
+	Name string `json:"name"`
+}
+
+func newTestRouter() *hx.Router {
+	r := hx.New()
+	r.GET("/users/{id}", hx.P("id", func(ctx context.Context, id string) (user, error) {
+		return user{Name: "user-" + id}, nil
+	}).JSON())
+	r.POST("/users", hx.G(func(ctx context.Context, req user) (user, error) {
+		hx.SetStatus(ctx, http.StatusCreated)
+		return req, nil
+	}).JSON())
+	r.GET("/whoami", func(w http.ResponseWriter, r *http.Request) error {
+		cookie, err := r.Cookie("session")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write([]byte(r.Header.Get("X-Tenant") + "/" + cookie.Value))
+		return err
+	})
+	return r
+}
+
+func TestClientJSON(t *testing.T) {
+	client := Client(newTestRouter())
+
+	var out user
+	resp, err := client.GET("/users/7").JSON(&out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	if out.Name != "user-7" {
+		t.Errorf("expected name %s, got %s", "user-7", out.Name)
+	}
+}
+
+func TestClientBody(t *testing.T) {
+	client := Client(newTestRouter())
+
+	var out user
+	resp, err := client.POST("/users").Body(user{Name: "gopher"}).JSON(&out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	if out.Name != "gopher" {
+		t.Errorf("expected name %s, got %s", "gopher", out.Name)
+	}
+}
+
+func TestClientHeadersAndCookies(t *testing.T) {
+	client := Client(newTestRouter()).Header("X-Tenant", "acme")
+
+	resp, err := client.GET("/whoami").Cookie(&http.Cookie{Name: "session", Value: "s1"}).Do()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.String() != "acme/s1" {
+		t.Errorf("expected body %s, got %s", "acme/s1", resp.String())
+	}
+}
+
+func TestClientRequestCookiesDoNotLeak(t *testing.T) {
+	client := Client(newTestRouter())
+	for _, name := range []string{"a", "b", "c"} {
+		client.Cookie(&http.Cookie{Name: name, Value: name})
+	}
+
+	if _, err := client.GET("/whoami").Cookie(&http.Cookie{Name: "session", Value: "s1"}).Do(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, cookie := range client.cookies[:cap(client.cookies)] {
+		if cookie != nil && cookie.Name == "session" {
+			t.Fatalf("expected the request cookie not to be written into the client's cookies")
+		}
+	}
+}
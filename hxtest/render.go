@@ -0,0 +1,23 @@
+// Package hxtest provides utilities for testing hx handlers, routers, and renders.
+package hxtest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+// Render runs r.IntoResponse against an in-memory recorder and returns the recorded parts.
+// The error is the one returned by IntoResponse; the other values reflect whatever was
+// written before it occurred.
+//
+// Example:
+//
+//	status, header, body, err := hxtest.Render(httpx.JSONResponse{Data: user})
+func Render(r httpx.ResponseRender) (status int, header http.Header, body []byte, err error) {
+	recorder := httptest.NewRecorder()
+	err = r.IntoResponse(recorder)
+	result := recorder.Result()
+	return result.StatusCode, result.Header, recorder.Body.Bytes(), err
+}
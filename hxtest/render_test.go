@@ -0,0 +1,49 @@
+package hxtest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+func TestRenderJSON(t *testing.T) {
+	status, header, body, err := Render(httpx.JSONResponse{
+		Data:       map[string]string{"name": "gopher"},
+		StatusCode: http.StatusCreated,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status != http.StatusCreated {
+		t.Errorf("expected status code %d, got %d", http.StatusCreated, status)
+	}
+
+	if header.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("expected json content type, got %s", header.Get("Content-Type"))
+	}
+
+	if string(body) != "{\"name\":\"gopher\"}\n" {
+		t.Errorf("unexpected body %q", body)
+	}
+}
+
+func TestRenderString(t *testing.T) {
+	status, header, body, err := Render(httpx.StringResponse{Data: "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if status != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, status)
+	}
+
+	if header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("expected text content type, got %s", header.Get("Content-Type"))
+	}
+
+	if string(body) != "hello" {
+		t.Errorf("expected body %s, got %s", "hello", body)
+	}
+}
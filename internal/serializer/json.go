@@ -5,8 +5,10 @@
 package serializer
 
 import (
+	"context"
 	"encoding/json"
 	"io"
+	"net/http"
 )
 
 // Serializer defines an interface for encoding and decoding data.
@@ -64,3 +66,41 @@ func SetJSONSerializer(s Serializer) {
 	}
 	jsonSerializerInstance = s
 }
+
+// jsonKey is the context key under which a per-request JSON serializer is stored.
+type jsonKey struct{}
+
+// WithJSONSerializer returns a copy of ctx carrying s as the JSON serializer of the request.
+func WithJSONSerializer(ctx context.Context, s Serializer) context.Context {
+	return context.WithValue(ctx, jsonKey{}, s)
+}
+
+// JSONSerializerFromContext returns the JSON serializer stored in ctx by WithJSONSerializer,
+// or the global one if there is none.
+func JSONSerializerFromContext(ctx context.Context) Serializer {
+	if s, ok := ctx.Value(jsonKey{}).(Serializer); ok {
+		return s
+	}
+	return JSONSerializer()
+}
+
+// JSONSerializerCarrier is implemented by response writers carrying the JSON serializer of their request.
+type JSONSerializerCarrier interface {
+	JSONSerializer() Serializer
+}
+
+// JSONSerializerFromWriter returns the JSON serializer carried by w, or by a writer it wraps
+// according to Unwrap, or the global one if there is none.
+func JSONSerializerFromWriter(w http.ResponseWriter) Serializer {
+	for w != nil {
+		if carrier, ok := w.(JSONSerializerCarrier); ok {
+			return carrier.JSONSerializer()
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = unwrapper.Unwrap()
+	}
+	return JSONSerializer()
+}
@@ -1,8 +1,28 @@
 package hx
 
 import (
+	"bytes"
+	"cmp"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/eatmoreapple/hx/binding"
 )
 
 // Middleware represents a function that wraps a HandlerFunc and returns a new HandlerFunc.
@@ -41,3 +61,1229 @@ func WithValue[T comparable](key T, value any) Middleware {
 		}
 	}
 }
+
+// Timeout is a middleware that gives the request context a deadline d from now.
+// The handler is not interrupted; it is expected to observe the context, and when it returns the
+// resulting context.DeadlineExceeded error the default error handler responds with 504 Gateway Timeout.
+// Handlers can read the deadline with Deadline to budget downstream calls.
+func Timeout(d time.Duration) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+			return handlerFunc(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// Deadline returns the time when work done on behalf of ctx should be canceled,
+// and false when no deadline is set. It is a shorthand for ctx.Deadline, typically used
+// to derive timeouts for downstream calls from what is left of the request budget:
+//
+//	if deadline, ok := hx.Deadline(ctx); ok {
+//	    client.Timeout = time.Until(deadline)
+//	}
+func Deadline(ctx context.Context) (time.Time, bool) {
+	return ctx.Deadline()
+}
+
+// boundKey is the context key under which BindFirst stores the bound value of type T.
+type boundKey[T any] struct{}
+
+// StripPrefix is a middleware that removes prefix from the request path, and the raw path
+// when set, answering 404 Not Found to requests outside of it. It suits applications served
+// behind a reverse proxy under a sub-path. To change which route matches, it must run before
+// routing, so register it with WithPreRouting rather than Use:
+//
+//	r := hx.New(hx.WithPreRouting(hx.StripPrefix("/app")))
+//	r.GET("/users", listUsers) // serves /app/users
+//
+// Unlike Mount, which strips a prefix for one sub-handler, it applies to every route.
+func StripPrefix(prefix string) Middleware {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			p, ok := trimPathPrefix(r.URL.Path, prefix)
+			rp, rawOK := trimPathPrefix(r.URL.RawPath, prefix)
+			if !ok || r.URL.RawPath != "" && !rawOK {
+				http.NotFound(w, r)
+				return nil
+			}
+			r2 := new(http.Request)
+			*r2 = *r
+			r2.URL = new(url.URL)
+			*r2.URL = *r.URL
+			r2.URL.Path = p
+			if r.URL.RawPath != "" {
+				r2.URL.RawPath = rp
+			}
+			return handlerFunc(w, r2)
+		}
+	}
+}
+
+// trimPathPrefix removes prefix from path on a segment boundary, so "/app" strips "/app/users"
+// but not "/application". The result always starts with a slash.
+func trimPathPrefix(path, prefix string) (string, bool) {
+	rest, ok := strings.CutPrefix(path, prefix)
+	if !ok || rest != "" && rest[0] != '/' {
+		return "", false
+	}
+	if rest == "" {
+		rest = "/"
+	}
+	return rest, true
+}
+
+// BindFirst is a middleware that binds the request into a value of type T before the
+// middleware that follows it runs, instead of inside the handler. Context values contributed
+// by httpx.ContextEnricher extractors of T, such as a resolved tenant, are therefore visible to
+// downstream middleware. The bound value is stored in the context and can be read with Bound;
+// typed handlers whose request type is T reuse it rather than binding again.
+//
+// Example:
+//
+//	r.POST("/orders", hx.Chain(hx.BindFirst[OrderRequest](), requireTenantQuota)(hx.G(createOrder).JSON()))
+func BindFirst[T any]() Middleware {
+	hasEnricher := containsContextEnricher(reflect.TypeFor[T](), make(map[reflect.Type]struct{}))
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			value, err := Bind[T](r)
+			if err != nil {
+				return err
+			}
+			ctx := r.Context()
+			if hasEnricher {
+				target := reflect.ValueOf(&value)
+				if reflect.TypeFor[T]().Kind() == reflect.Pointer {
+					target = target.Elem()
+				}
+				ctx = enrichContext(ctx, target, make(map[enrichVisit]struct{}))
+			}
+			ctx = context.WithValue(ctx, boundKey[T]{}, value)
+			return handlerFunc(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// Bound returns the value bound by BindFirst for type T.
+// The boolean is false if BindFirst[T] did not run for the request.
+func Bound[T any](ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(boundKey[T]{}).(T)
+	return value, ok
+}
+
+// bufferedBodyKey is the context key under which BufferBody stores the request body bytes.
+type bufferedBodyKey struct{}
+
+// BufferBody is a middleware that reads the whole request body into memory, at most maxBytes,
+// and replaces r.Body with a reader over the buffered bytes.
+// The bytes are also stored in the request context and can be retrieved with BufferedBody,
+// so other middleware can inspect the body without consuming it for the handler.
+// If the body exceeds maxBytes, an *http.MaxBytesError is returned.
+func BufferBody(maxBytes int64) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Body == nil || r.Body == http.NoBody {
+				return handlerFunc(w, r)
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBytes))
+			_ = r.Body.Close()
+			if err != nil {
+				return err
+			}
+			r = r.WithContext(context.WithValue(r.Context(), bufferedBodyKey{}, body))
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			return handlerFunc(w, r)
+		}
+	}
+}
+
+// BufferedBody returns the request body bytes stored by BufferBody.
+// The returned slice must not be modified.
+// The boolean is false if the body was not buffered.
+func BufferedBody(ctx context.Context) ([]byte, bool) {
+	body, ok := ctx.Value(bufferedBodyKey{}).([]byte)
+	return body, ok
+}
+
+// DefaultBodyLogSize is the number of bytes of each body BodyLog records when BodyLogOptions.MaxBodySize is zero.
+const DefaultBodyLogSize = 64 << 10
+
+// BodyLogEntry is the record BodyLog passes to its sink once a request has been handled.
+type BodyLogEntry struct {
+	Request           *http.Request // Request is the handled request, its body already consumed
+	StatusCode        int           // StatusCode written by the handler, 0 if it returned an error without writing
+	RequestBody       []byte        // RequestBody is the redacted request body, at most MaxBodySize bytes
+	ResponseBody      []byte        // ResponseBody is the redacted response body, at most MaxBodySize bytes
+	RequestTruncated  bool          // RequestTruncated reports whether RequestBody was cut at MaxBodySize
+	ResponseTruncated bool          // ResponseTruncated reports whether ResponseBody was cut at MaxBodySize
+	Err               error         // Err is the error returned by the handler
+}
+
+// BodyLogOptions configures the BodyLog middleware.
+type BodyLogOptions struct {
+	// MaxBodySize caps the bytes recorded from each body. Defaults to DefaultBodyLogSize.
+	// Bodies are passed through in full regardless of the cap.
+	MaxBodySize int
+	// Redact rewrites a recorded body before it reaches the sink, e.g. RedactJSONFields.
+	// It is applied to request and response bodies alike. Nil records bodies verbatim.
+	Redact func(body []byte) []byte
+	// Sink receives an entry for every request. BodyLog does nothing when it is nil.
+	Sink func(entry BodyLogEntry)
+}
+
+// BodyLog is a middleware that records the request and response bodies for audit logging.
+// The request body is read up to the size cap and restored, so the handler still receives all of it,
+// and the response is teed, so the client still receives it unmodified. Both recordings go through
+// opts.Redact and are then passed to opts.Sink.
+//
+// Example:
+//
+//	r.Use(hx.BodyLog(hx.BodyLogOptions{
+//	    Redact: hx.RedactJSONFields("password", "token"),
+//	    Sink:   func(e hx.BodyLogEntry) { auditLog.Info("request", "path", e.Request.URL.Path, "body", e.RequestBody) },
+//	}))
+func BodyLog(opts BodyLogOptions) Middleware {
+	limit := cmp.Or(opts.MaxBodySize, DefaultBodyLogSize)
+	redact := func(body []byte) []byte {
+		if opts.Redact == nil || len(body) == 0 {
+			return body
+		}
+		return opts.Redact(body)
+	}
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		if opts.Sink == nil {
+			return handlerFunc
+		}
+		return func(w http.ResponseWriter, r *http.Request) error {
+			var requestBody []byte
+			if r.Body != nil && r.Body != http.NoBody {
+				head, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+				if err != nil {
+					return err
+				}
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(head), r.Body), r.Body}
+				requestBody = head
+			}
+
+			capture := &captureResponseWriter{ResponseWriter: w, limit: limit}
+			err := handlerFunc(capture, r)
+
+			entry := BodyLogEntry{
+				Request:           r,
+				StatusCode:        capture.status,
+				RequestBody:       redact(requestBody[:min(len(requestBody), limit)]),
+				ResponseBody:      redact(capture.body.Bytes()),
+				RequestTruncated:  len(requestBody) > limit,
+				ResponseTruncated: capture.truncated,
+				Err:               err,
+			}
+			opts.Sink(entry)
+			return err
+		}
+	}
+}
+
+// RedactJSONFields returns a redaction function for BodyLogOptions.Redact that replaces the values
+// of the named object keys, matched case-insensitively at any depth, with "[REDACTED]".
+// Bodies that are not valid JSON, including truncated ones, are dropped rather than risk leaking them.
+func RedactJSONFields(fields ...string) func(body []byte) []byte {
+	return func(body []byte) []byte {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+		var document any
+		if err := decoder.Decode(&document); err != nil {
+			return nil
+		}
+		redacted, err := json.Marshal(redactJSON(document, fields))
+		if err != nil {
+			return nil
+		}
+		return redacted
+	}
+}
+
+// redactJSON replaces the values of the named keys in a decoded JSON document.
+func redactJSON(value any, fields []string) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, item := range value {
+			if slices.ContainsFunc(fields, func(field string) bool { return strings.EqualFold(field, key) }) {
+				value[key] = "[REDACTED]"
+				continue
+			}
+			value[key] = redactJSON(item, fields)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = redactJSON(item, fields)
+		}
+	}
+	return value
+}
+
+// RequireContentType is a middleware that rejects requests whose Content-Type media type
+// is not one of mimeTypes, before any binding happens. It returns an error wrapping
+// binding.ErrUnsupportedMediaType, which the default error handler renders as 415 Unsupported Media Type.
+// Media types are compared case-insensitively and parameters such as charset are ignored.
+// It can wrap a single handler to enforce the content type per route:
+//
+//	r.POST("/users", hx.RequireContentType("application/json")(hx.G(createUser).JSON()))
+func RequireContentType(mimeTypes ...string) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			if err == nil {
+				for _, mimeType := range mimeTypes {
+					if strings.EqualFold(mediaType, mimeType) {
+						return handlerFunc(w, r)
+					}
+				}
+			}
+			return fmt.Errorf("%w: %s", binding.ErrUnsupportedMediaType, mediaType)
+		}
+	}
+}
+
+// CORSOptions configures the CORS middleware.
+type CORSOptions struct {
+	// AllowedOrigins lists the origins allowed to make cross-origin requests, compared
+	// case-insensitively. "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedMethods is answered to preflight requests.
+	// Defaults to GET, HEAD, POST, PUT, PATCH and DELETE.
+	AllowedMethods []string
+	// AllowedHeaders is answered to preflight requests.
+	// Defaults to the headers requested by the preflight.
+	AllowedHeaders []string
+	// ExposedHeaders lists the response headers scripts may read.
+	ExposedHeaders []string
+	// AllowCredentials allows requests with cookies or HTTP authentication.
+	// It cannot be combined with the "*" origin, which would let any site read authenticated responses.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache preflight responses. Zero omits the header.
+	MaxAge time.Duration
+}
+
+// CORS is a middleware implementing cross-origin resource sharing for the allowed origins.
+// Preflight requests, OPTIONS requests carrying Access-Control-Request-Method, are answered
+// with 204 No Content and the allowed methods and headers, without reaching binding or the handler.
+// Other requests from an allowed origin get the Access-Control-Allow-Origin header and proceed.
+// Requests without an Origin header, or from origins that are not allowed, proceed unchanged.
+//
+// Preflights are usually sent for paths that only register other methods, which ServeMux would
+// answer with 405 Method Not Allowed, so register CORS with WithPreRouting rather than Use:
+//
+//	r := hx.New(hx.WithPreRouting(hx.CORS(hx.CORSOptions{AllowedOrigins: []string{"https://app.example.com"}})))
+//
+// Panics if AllowCredentials is set while AllowedOrigins contains "*".
+func CORS(opts CORSOptions) Middleware {
+	anyOrigin := slices.Contains(opts.AllowedOrigins, "*")
+	if anyOrigin && opts.AllowCredentials {
+		panic("hx: CORS cannot allow credentials for any origin, list the allowed origins instead")
+	}
+	methods := strings.Join(opts.AllowedMethods, ", ")
+	if methods == "" {
+		methods = "GET, HEAD, POST, PUT, PATCH, DELETE"
+	}
+	headers := strings.Join(opts.AllowedHeaders, ", ")
+	exposed := strings.Join(opts.ExposedHeaders, ", ")
+
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				return handlerFunc(w, r)
+			}
+			w.Header().Add("Vary", "Origin")
+			allowed := anyOrigin || slices.ContainsFunc(opts.AllowedOrigins, func(o string) bool {
+				return strings.EqualFold(o, origin)
+			})
+			if !allowed {
+				return handlerFunc(w, r)
+			}
+
+			if anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if r.Method != http.MethodOptions || r.Header.Get("Access-Control-Request-Method") == "" {
+				if exposed != "" {
+					w.Header().Set("Access-Control-Expose-Headers", exposed)
+				}
+				return handlerFunc(w, r)
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", methods)
+			if headers != "" {
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+			} else if requested := r.Header.Get("Access-Control-Request-Headers"); requested != "" {
+				w.Header().Set("Access-Control-Allow-Headers", requested)
+			}
+			if opts.MaxAge > 0 {
+				w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(opts.MaxAge.Seconds())))
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return nil
+		}
+	}
+}
+
+// PreloadLink describes an asset announced to the browser with a Link preload header.
+type PreloadLink struct {
+	URL         string // URL of the asset, e.g. "/static/style.css"
+	As          string // As is the destination, e.g. "style", "script", "font" or "image"
+	Type        string // Type is the optional MIME type, e.g. "font/woff2"
+	CrossOrigin bool   // CrossOrigin adds the crossorigin attribute, required for fonts
+}
+
+// String returns the Link header value, e.g. "</style.css>; rel=preload; as=style".
+func (l PreloadLink) String() string {
+	var sb strings.Builder
+	sb.WriteString("<" + l.URL + ">; rel=preload")
+	if l.As != "" {
+		sb.WriteString("; as=" + l.As)
+	}
+	if l.Type != "" {
+		sb.WriteString(`; type="` + l.Type + `"`)
+	}
+	if l.CrossOrigin {
+		sb.WriteString("; crossorigin")
+	}
+	return sb.String()
+}
+
+// Preload is a middleware that adds a Link preload header for each of links before the handler
+// writes its response, so browsers start fetching critical assets while parsing the page.
+// Since HTTP/2 server push is deprecated, the assets are only announced, not pushed.
+//
+// Example:
+//
+//	r.GET("/", hx.Preload(
+//	    hx.PreloadLink{URL: "/static/style.css", As: "style"},
+//	    hx.PreloadLink{URL: "/static/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: true},
+//	)(home))
+func Preload(links ...PreloadLink) Middleware {
+	values := make([]string, len(links))
+	for i, link := range links {
+		values[i] = link.String()
+	}
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			for _, value := range values {
+				w.Header().Add("Link", value)
+			}
+			return handlerFunc(w, r)
+		}
+	}
+}
+
+// ErrInvalidHeader is wrapped by the error returned by RequireHeader when the header is missing
+// or invalid. The router's default error handler responds to it with 400 Bad Request.
+var ErrInvalidHeader = errors.New("hx: invalid header")
+
+// RequireHeader is a middleware that requires the request to carry the header name, returning an
+// error wrapping ErrInvalidHeader before the handler runs when it is absent or when validate,
+// if not nil, rejects its value. Several headers are required by chaining the middleware:
+//
+//	r.Use(
+//	    hx.RequireHeader("X-Api-Version", func(v string) bool { return v == "1" || v == "2" }),
+//	    hx.RequireHeader("X-Request-Id", nil),
+//	)
+func RequireHeader(name string, validate func(value string) bool) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			value := r.Header.Get(name)
+			if value == "" {
+				return fmt.Errorf("%w: missing %s", ErrInvalidHeader, name)
+			}
+			if validate != nil && !validate(value) {
+				return fmt.Errorf("%w: %s", ErrInvalidHeader, name)
+			}
+			return handlerFunc(w, r)
+		}
+	}
+}
+
+// RedirectHTTPSOptions configures the RedirectHTTPS middleware.
+type RedirectHTTPSOptions struct {
+	// Host overrides the host of the redirect target. Defaults to the request host without its port.
+	Host string
+	// Port is the HTTPS port of the redirect target. Empty means the default port 443.
+	Port string
+	// StatusCode is the redirect status code. Defaults to 308 Permanent Redirect,
+	// which preserves the method and body; use 301 for legacy clients.
+	StatusCode int
+}
+
+// RedirectHTTPS is a middleware that redirects insecure requests to their https:// equivalent.
+// A request is considered secure if it arrived over TLS or if a proxy in front of the server
+// set X-Forwarded-Proto to https. Secure requests are passed to the next handler unchanged.
+func RedirectHTTPS(opts RedirectHTTPSOptions) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+				return handlerFunc(w, r)
+			}
+
+			host := opts.Host
+			if host == "" {
+				host = r.Host
+				if h, _, err := net.SplitHostPort(r.Host); err == nil {
+					host = h
+				}
+			}
+			if opts.Port != "" && opts.Port != "443" {
+				host = net.JoinHostPort(host, opts.Port)
+			}
+
+			target := "https://" + host + r.URL.RequestURI()
+			http.Redirect(w, r, target, cmp.Or(opts.StatusCode, http.StatusPermanentRedirect))
+			return nil
+		}
+	}
+}
+
+// ErrMalformedBody is wrapped by errors returned when a compressed request body cannot be decoded.
+// The router's default error handler responds to it with 400 Bad Request.
+var ErrMalformedBody = errors.New("hx: malformed request body")
+
+// Decompress is a middleware that transparently decompresses request bodies sent with
+// Content-Encoding: gzip, so downstream binders read the decoded data.
+// The Content-Encoding and Content-Length headers are removed once the body is wrapped.
+// Corrupt data yields errors wrapping ErrMalformedBody.
+func Decompress() Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+			if encoding != "gzip" && encoding != "x-gzip" || r.Body == nil || r.Body == http.NoBody {
+				return handlerFunc(w, r)
+			}
+			reader, err := gzip.NewReader(r.Body)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrMalformedBody, err)
+			}
+			defer reader.Close()
+
+			r.Body = &decompressedBody{reader: reader, closer: r.Body}
+			r.Header.Del("Content-Encoding")
+			r.Header.Del("Content-Length")
+			r.ContentLength = -1
+			return handlerFunc(w, r)
+		}
+	}
+}
+
+// decompressedBody reads decoded data and reports decoding failures as ErrMalformedBody.
+type decompressedBody struct {
+	reader io.Reader
+	closer io.Closer
+}
+
+func (d *decompressedBody) Read(p []byte) (int, error) {
+	n, err := d.reader.Read(p)
+	if err != nil && err != io.EOF {
+		err = fmt.Errorf("%w: %v", ErrMalformedBody, err)
+	}
+	return n, err
+}
+
+func (d *decompressedBody) Close() error {
+	return d.closer.Close()
+}
+
+// ErrOverloaded is returned by the Concurrency middleware when the concurrency limit is reached.
+// The router's default error handler responds to it with 503 Service Unavailable.
+var ErrOverloaded = errors.New("hx: too many concurrent requests")
+
+// ConcurrencyOption defines a function type for configuring the Concurrency middleware.
+type ConcurrencyOption func(*concurrencyConfig)
+
+// concurrencyConfig holds the settings applied by ConcurrencyOption.
+type concurrencyConfig struct {
+	// wait is how long a request waits for a free slot, zero rejects immediately
+	wait time.Duration
+}
+
+// WithConcurrencyWait makes requests wait up to d for a free slot before being rejected,
+// instead of being rejected as soon as the limit is reached.
+func WithConcurrencyWait(d time.Duration) ConcurrencyOption {
+	return func(c *concurrencyConfig) {
+		c.wait = d
+	}
+}
+
+// Concurrency is a middleware that limits the number of requests handled at the same time to max.
+// When the limit is reached, requests are rejected with ErrOverloaded, or wait for a slot
+// when WithConcurrencyWait is set. A slot is always released when the handler returns,
+// even if it panics. Panics if max is not positive.
+//
+// Example:
+//
+//	r.Use(hx.Concurrency(100, hx.WithConcurrencyWait(50*time.Millisecond)))
+func Concurrency(max int, options ...ConcurrencyOption) Middleware {
+	if max <= 0 {
+		panic(fmt.Sprintf("hx: concurrency limit must be positive, got %d", max))
+	}
+	config := concurrencyConfig{}
+	for _, opt := range options {
+		opt(&config)
+	}
+	semaphore := make(chan struct{}, max)
+
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if err := acquire(r.Context(), semaphore, config.wait); err != nil {
+				return err
+			}
+			defer func() { <-semaphore }()
+			return handlerFunc(w, r)
+		}
+	}
+}
+
+// acquire takes a slot from semaphore, waiting up to wait for one to become free.
+func acquire(ctx context.Context, semaphore chan struct{}, wait time.Duration) error {
+	select {
+	case semaphore <- struct{}{}:
+		return nil
+	default:
+	}
+	if wait <= 0 {
+		return ErrOverloaded
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case semaphore <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return ErrOverloaded
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IdempotentResponse is a response recorded by the Idempotency middleware.
+type IdempotentResponse struct {
+	StatusCode int         // StatusCode is the status code written by the handler
+	Header     http.Header // Header holds the response headers
+	Body       []byte      // Body is the response body
+}
+
+// IdempotencyStore persists the responses recorded by the Idempotency middleware.
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Get returns the response stored under key, and false if there is none or it has expired.
+	Get(ctx context.Context, key string) (*IdempotentResponse, bool, error)
+
+	// Set stores the response under key for the duration of ttl.
+	Set(ctx context.Context, key string, response *IdempotentResponse, ttl time.Duration) error
+}
+
+// DefaultIdempotencyTTL is how long the Idempotency middleware keeps responses by default.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// IdempotencyOption defines a function type for configuring the Idempotency middleware.
+type IdempotencyOption func(*idempotencyConfig)
+
+// idempotencyConfig holds the settings applied by IdempotencyOption.
+type idempotencyConfig struct {
+	// ttl is how long responses are kept in the store
+	ttl time.Duration
+	// scope returns the caller a request is made by, nil shares keys between callers
+	scope func(*http.Request) string
+}
+
+// WithIdempotencyTTL sets how long recorded responses are kept, DefaultIdempotencyTTL by default.
+func WithIdempotencyTTL(ttl time.Duration) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.ttl = ttl
+	}
+}
+
+// WithIdempotencyScope scopes keys by caller: scope returns the identity a request is made by,
+// e.g. the authenticated user or API client, and responses are only replayed to the same identity.
+// Without it, any client sending a key already used by another client receives that client's response.
+//
+// Example:
+//
+//	hx.Idempotency(store, hx.WithIdempotencyScope(func(r *http.Request) string {
+//	    return auth.UserID(r.Context())
+//	}))
+func WithIdempotencyScope(scope func(*http.Request) string) IdempotencyOption {
+	return func(c *idempotencyConfig) {
+		c.scope = scope
+	}
+}
+
+// Idempotency is a middleware that replays responses for repeated requests carrying the same
+// Idempotency-Key header. The first response for a key is recorded in store, scoped to the request
+// method and path, and later requests with that key receive the recorded status, headers and body
+// without running the handler. Replayed responses carry an "Idempotent-Replayed: true" header.
+// Set-Cookie headers are never recorded.
+//
+// Keys are chosen by clients, so unless every caller is trusted, use WithIdempotencyScope to keep
+// the responses of different identities apart: a store must not be shared across identities without it.
+//
+// Only successful responses are recorded: a handler returning an error, or writing a 5xx status,
+// can be retried with the same key. Requests without the header are passed through.
+// Concurrent requests with the same key are not coordinated and may both run the handler.
+func Idempotency(store IdempotencyStore, options ...IdempotencyOption) Middleware {
+	config := idempotencyConfig{ttl: DefaultIdempotencyTTL}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			key := r.Header.Get("Idempotency-Key")
+			if key == "" {
+				return handlerFunc(w, r)
+			}
+			key = r.Method + " " + r.URL.Path + " " + key
+			if config.scope != nil {
+				key = config.scope(r) + " " + key
+			}
+
+			cached, ok, err := store.Get(r.Context(), key)
+			if err != nil {
+				return err
+			}
+			if ok {
+				for name, values := range cached.Header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("Idempotent-Replayed", "true")
+				w.WriteHeader(cached.StatusCode)
+				_, err := w.Write(cached.Body)
+				return err
+			}
+
+			capture := &captureResponseWriter{ResponseWriter: w}
+			if err := handlerFunc(capture, r); err != nil {
+				return err
+			}
+			status := cmp.Or(capture.status, http.StatusOK)
+			if status >= http.StatusInternalServerError {
+				return nil
+			}
+			header := w.Header().Clone()
+			header.Del("Set-Cookie")
+			return store.Set(r.Context(), key, &IdempotentResponse{
+				StatusCode: status,
+				Header:     header,
+				Body:       capture.body.Bytes(),
+			}, config.ttl)
+		}
+	}
+}
+
+// captureResponseWriter is an http.ResponseWriter that records the status and body it writes.
+type captureResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+
+	// limit caps the recorded body, 0 records all of it
+	limit     int
+	truncated bool
+}
+
+// WriteHeader records the status code and writes it to the underlying ResponseWriter.
+func (c *captureResponseWriter) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+// Write records b and writes it to the underlying ResponseWriter.
+func (c *captureResponseWriter) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	recorded := b
+	if c.limit > 0 && c.body.Len()+len(recorded) > c.limit {
+		recorded = recorded[:c.limit-c.body.Len()]
+		c.truncated = true
+	}
+	c.body.Write(recorded)
+	return c.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (c *captureResponseWriter) Unwrap() http.ResponseWriter {
+	return c.ResponseWriter
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore, suitable for tests and single instances.
+// It keeps at most MaxEntries responses: once full, expired responses are removed, and if none
+// has expired, the response closest to expiring is evicted to make room.
+type MemoryIdempotencyStore struct {
+	// MaxEntries is the maximum number of responses kept, DefaultMemoryStoreEntries if not positive.
+	MaxEntries int
+
+	store memoryStore[*IdempotentResponse]
+}
+
+// NewMemoryIdempotencyStore creates an empty MemoryIdempotencyStore.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{}
+}
+
+// Get implements IdempotencyStore. Expired entries are removed when they are looked up.
+func (m *MemoryIdempotencyStore) Get(_ context.Context, key string) (*IdempotentResponse, bool, error) {
+	response, ok := m.store.get(key)
+	return response, ok, nil
+}
+
+// Set implements IdempotencyStore.
+func (m *MemoryIdempotencyStore) Set(_ context.Context, key string, response *IdempotentResponse, ttl time.Duration) error {
+	m.store.set(key, response, ttl, m.MaxEntries)
+	return nil
+}
+
+// DefaultMemoryStoreEntries is the default maximum number of entries kept by
+// MemoryCacheStore and MemoryIdempotencyStore.
+const DefaultMemoryStoreEntries = 10000
+
+// memoryStore is a map of values expiring after a TTL, safe for concurrent use.
+// It holds at most a fixed number of entries, so keys chosen by clients cannot grow it without bound.
+type memoryStore[V any] struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry[V]
+}
+
+// memoryEntry is a value stored in a memoryStore.
+type memoryEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// get returns the value stored under key. Expired entries are removed when they are looked up.
+func (m *memoryStore[V]) get(key string) (V, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		delete(m.entries, key)
+		var zero V
+		return zero, false
+	}
+	return entry.value, true
+}
+
+// set stores value under key for the duration of ttl. When the store already holds maxEntries
+// entries, or DefaultMemoryStoreEntries if maxEntries is not positive, expired entries are removed,
+// and if none had expired, the entry closest to expiring is evicted.
+func (m *memoryStore[V]) set(key string, value V, ttl time.Duration, maxEntries int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]memoryEntry[V])
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultMemoryStoreEntries
+	}
+	if _, ok := m.entries[key]; !ok && len(m.entries) >= maxEntries {
+		m.evict()
+	}
+	m.entries[key] = memoryEntry[V]{value: value, expires: time.Now().Add(ttl)}
+}
+
+// evict removes the expired entries, or the entry closest to expiring if none has expired.
+func (m *memoryStore[V]) evict() {
+	now := time.Now()
+	expired := false
+	var (
+		oldest   string
+		earliest time.Time
+	)
+	for key, entry := range m.entries {
+		if now.After(entry.expires) {
+			delete(m.entries, key)
+			expired = true
+			continue
+		}
+		if earliest.IsZero() || entry.expires.Before(earliest) {
+			oldest, earliest = key, entry.expires
+		}
+	}
+	if !expired && !earliest.IsZero() {
+		delete(m.entries, oldest)
+	}
+}
+
+// Gzip is a middleware that compresses response bodies with gzip for clients sending
+// "Accept-Encoding: gzip". Every response gets "Vary: Accept-Encoding" so caches keep the
+// compressed and uncompressed variants apart. Responses without a body (204, 304) and responses
+// that already set a Content-Encoding are left untouched. A strong ETag set by inner middleware
+// or handlers is weakened when the body is compressed, as the bytes sent differ from those it was
+// computed over.
+//
+// When combined with ETag, Gzip must wrap ETag so the tag is computed over the uncompressed body:
+//
+//	r.Use(hx.Gzip(), hx.ETag())
+func Gzip() Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if !acceptsGzip(r) {
+				return handlerFunc(w, r)
+			}
+			gw := &gzipResponseWriter{ResponseWriter: w}
+			defer gw.close()
+			return handlerFunc(gw, r)
+		}
+	}
+}
+
+// gzipResponseWriter is an http.ResponseWriter that compresses the body it writes.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	writer      *gzip.Writer
+	status      int
+	wroteHeader bool
+	compress    bool
+}
+
+// WriteHeader records the status code. It is written with the first body chunk, so the
+// Content-Type can still be sniffed from the uncompressed bytes.
+func (g *gzipResponseWriter) WriteHeader(status int) {
+	if g.wroteHeader || g.status != 0 {
+		return
+	}
+	// informational responses are followed by the final one
+	if status >= http.StatusContinue && status < http.StatusOK {
+		g.ResponseWriter.WriteHeader(status)
+		return
+	}
+	g.status = status
+}
+
+// writeHeader decides whether the body is compressed and writes the status code.
+// net/http does not sniff the Content-Type of encoded responses, so a missing one is
+// detected from chunk, the first uncompressed bytes of the body, before compression is enabled.
+func (g *gzipResponseWriter) writeHeader(chunk []byte) {
+	if g.wroteHeader {
+		return
+	}
+	g.wroteHeader = true
+	status := cmp.Or(g.status, http.StatusOK)
+	header := g.Header()
+	if status != http.StatusNoContent && status != http.StatusNotModified && header.Get("Content-Encoding") == "" {
+		if _, ok := header["Content-Type"]; !ok && len(chunk) > 0 {
+			header.Set("Content-Type", http.DetectContentType(chunk))
+		}
+		g.compress = true
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+	}
+	g.ResponseWriter.WriteHeader(status)
+}
+
+// Write compresses b when compression is enabled for the response.
+func (g *gzipResponseWriter) Write(b []byte) (int, error) {
+	g.writeHeader(b)
+	if !g.compress {
+		return g.ResponseWriter.Write(b)
+	}
+	if g.writer == nil {
+		g.writer = gzip.NewWriter(g.ResponseWriter)
+	}
+	return g.writer.Write(b)
+}
+
+// Flush flushes the compressed data written so far to the client.
+func (g *gzipResponseWriter) Flush() {
+	g.writeHeader(nil)
+	if g.writer != nil {
+		_ = g.writer.Flush()
+	}
+	_ = http.NewResponseController(g.ResponseWriter).Flush()
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (g *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return g.ResponseWriter
+}
+
+// close writes a status code recorded without a body, uncompressed, and terminates the gzip stream,
+// if one was started.
+func (g *gzipResponseWriter) close() {
+	if !g.wroteHeader && g.status != 0 {
+		g.wroteHeader = true
+		g.ResponseWriter.WriteHeader(g.status)
+	}
+	if g.writer != nil {
+		_ = g.writer.Close()
+	}
+}
+
+// acceptsGzip reports whether the Accept-Encoding header allows gzip.
+func acceptsGzip(req *http.Request) bool {
+	for _, part := range strings.Split(req.Header.Get("Accept-Encoding"), ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		q, found := strings.CutPrefix(strings.TrimSpace(params), "q=")
+		if !found {
+			return true
+		}
+		weight, err := strconv.ParseFloat(q, 64)
+		return err == nil && weight > 0
+	}
+	return false
+}
+
+// ETag is a middleware that sets an ETag header on successful GET and HEAD responses,
+// computed from a hash of the body, and answers requests whose If-None-Match matches it
+// with 304 Not Modified. An ETag set by the handler is kept as is.
+// The response is buffered to compute the hash, so ETag is not suited to streaming responses.
+//
+// Place ETag inside Gzip so the tag identifies the uncompressed content; see Gzip.
+func ETag() Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				return handlerFunc(w, r)
+			}
+			buffer := &bufferedResponseWriter{header: w.Header()}
+			if err := handlerFunc(buffer, r); err != nil {
+				return err
+			}
+			status := cmp.Or(buffer.status, http.StatusOK)
+			if status == http.StatusOK {
+				etag := w.Header().Get("ETag")
+				if etag == "" {
+					sum := sha256.Sum256(buffer.body.Bytes())
+					etag = `"` + hex.EncodeToString(sum[:16]) + `"`
+					w.Header().Set("ETag", etag)
+				}
+				if etagMatches(r.Header.Get("If-None-Match"), etag) {
+					w.Header().Del("Content-Length")
+					w.WriteHeader(http.StatusNotModified)
+					return nil
+				}
+			}
+			w.WriteHeader(status)
+			_, err := w.Write(buffer.body.Bytes())
+			return err
+		}
+	}
+}
+
+// etagMatches reports whether the If-None-Match header value matches etag,
+// using the weak comparison required for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponseWriter is an http.ResponseWriter that holds the status and body in memory.
+// Headers are written directly to the wrapped writer's header map.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the header map of the wrapped writer.
+func (b *bufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the status code.
+func (b *bufferedResponseWriter) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write appends p to the buffered body.
+func (b *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// CachedResponse is a response stored by the Cache middleware.
+type CachedResponse struct {
+	StatusCode int               // StatusCode is the status code written by the handler
+	Header     http.Header       // Header holds the response headers
+	Body       []byte            // Body is the response body
+	Vary       map[string]string // Vary holds the request header values named by the Vary response header
+}
+
+// CacheStore persists the responses stored by the Cache middleware.
+// Implementations must be safe for concurrent use.
+type CacheStore interface {
+	// Get returns the response stored under key, and false if there is none or it has expired.
+	Get(ctx context.Context, key string) (*CachedResponse, bool, error)
+
+	// Set stores the response under key for the duration of ttl.
+	Set(ctx context.Context, key string, response *CachedResponse, ttl time.Duration) error
+}
+
+// Cache is a middleware that caches successful GET and HEAD responses in store for ttl,
+// keyed by method and URL, and serves later matching requests from the store without running
+// the handler. Cached responses carry an "X-Cache: HIT" header.
+//
+// The Vary header of the response is honored: the request header values it names are stored
+// with the response, and a request with different values is treated as a miss whose response
+// replaces the entry. Responses with "Vary: *" or "Cache-Control: no-store" or "private" are not cached,
+// and clients sending "Cache-Control: no-store" bypass the cache entirely.
+//
+// Responses to requests carrying credentials, an Authorization or Cookie header, are only cached
+// when they are explicitly marked "Cache-Control: public", as they may be specific to the caller.
+// Set-Cookie headers are never stored.
+func Cache(store CacheStore, ttl time.Duration) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead ||
+				hasCacheDirective(r.Header, "no-store") {
+				return handlerFunc(w, r)
+			}
+			key := r.Method + " " + r.URL.RequestURI()
+
+			cached, ok, err := store.Get(r.Context(), key)
+			if err != nil {
+				return err
+			}
+			if ok && varyMatches(cached.Vary, r.Header) {
+				for name, values := range cached.Header {
+					w.Header()[name] = values
+				}
+				w.Header().Set("X-Cache", "HIT")
+				w.WriteHeader(cached.StatusCode)
+				_, err := w.Write(cached.Body)
+				return err
+			}
+
+			capture := &captureResponseWriter{ResponseWriter: w}
+			if err := handlerFunc(capture, r); err != nil {
+				return err
+			}
+			if cmp.Or(capture.status, http.StatusOK) != http.StatusOK ||
+				hasCacheDirective(w.Header(), "no-store") || hasCacheDirective(w.Header(), "private") {
+				return nil
+			}
+			if hasCredentials(r) && !hasCacheDirective(w.Header(), "public") {
+				return nil
+			}
+			vary, ok := varyValues(w.Header(), r.Header)
+			if !ok {
+				return nil
+			}
+			header := w.Header().Clone()
+			header.Del("Set-Cookie")
+			return store.Set(r.Context(), key, &CachedResponse{
+				StatusCode: http.StatusOK,
+				Header:     header,
+				Body:       capture.body.Bytes(),
+				Vary:       vary,
+			}, ttl)
+		}
+	}
+}
+
+// hasCredentials reports whether the request carries an Authorization or Cookie header.
+func hasCredentials(r *http.Request) bool {
+	return r.Header.Get("Authorization") != "" || r.Header.Get("Cookie") != ""
+}
+
+// hasCacheDirective reports whether the Cache-Control header contains directive.
+func hasCacheDirective(header http.Header, directive string) bool {
+	for _, value := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+			if strings.EqualFold(name, directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// varyValues returns the request header values named by the Vary response header.
+// It reports false for "Vary: *", which makes the response uncacheable.
+func varyValues(response, request http.Header) (map[string]string, bool) {
+	vary := make(map[string]string)
+	for _, value := range response.Values("Vary") {
+		for _, name := range strings.Split(value, ",") {
+			name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+			if name == "*" {
+				return nil, false
+			}
+			if name != "" {
+				vary[name] = request.Get(name)
+			}
+		}
+	}
+	return vary, true
+}
+
+// varyMatches reports whether the request headers have the values stored for a cached response.
+func varyMatches(vary map[string]string, request http.Header) bool {
+	for name, value := range vary {
+		if request.Get(name) != value {
+			return false
+		}
+	}
+	return true
+}
+
+// MemoryCacheStore is an in-memory CacheStore, suitable for tests and single instances.
+// It keeps at most MaxEntries responses: once full, expired responses are removed, and if none
+// has expired, the response closest to expiring is evicted to make room.
+type MemoryCacheStore struct {
+	// MaxEntries is the maximum number of responses kept, DefaultMemoryStoreEntries if not positive.
+	MaxEntries int
+
+	store memoryStore[*CachedResponse]
+}
+
+// NewMemoryCacheStore creates an empty MemoryCacheStore.
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{}
+}
+
+// Get implements CacheStore. Expired entries are removed when they are looked up.
+func (m *MemoryCacheStore) Get(_ context.Context, key string) (*CachedResponse, bool, error) {
+	response, ok := m.store.get(key)
+	return response, ok, nil
+}
+
+// Set implements CacheStore.
+func (m *MemoryCacheStore) Set(_ context.Context, key string, response *CachedResponse, ttl time.Duration) error {
+	m.store.set(key, response, ttl, m.MaxEntries)
+	return nil
+}
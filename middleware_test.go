@@ -0,0 +1,785 @@
+package hx
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eatmoreapple/hx/binding"
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+func TestTimeout(t *testing.T) {
+	var (
+		deadline time.Time
+		ok       bool
+	)
+	handler := Timeout(time.Minute)(func(w http.ResponseWriter, r *http.Request) error {
+		deadline, ok = Deadline(r.Context())
+		return nil
+	})
+
+	start := time.Now()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if err := handler(httptest.NewRecorder(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if !ok {
+		t.Fatal("expected a deadline inside the timeout middleware")
+	}
+
+	if deadline.Before(start.Add(time.Minute)) || deadline.After(time.Now().Add(time.Minute)) {
+		t.Errorf("expected deadline about a minute from %v, got %v", start, deadline)
+	}
+
+	if _, ok := Deadline(req.Context()); ok {
+		t.Error("expected no deadline outside the timeout middleware")
+	}
+}
+
+func TestBufferBody(t *testing.T) {
+	type Request struct {
+		Name string `json:"name"`
+	}
+
+	var logged string
+	logger := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			body, ok := BufferedBody(r.Context())
+			if !ok {
+				t.Error("expected body to be buffered")
+			}
+			logged = string(body)
+			return next(w, r)
+		}
+	}
+
+	r := New(WithMiddleware(BufferBody(1<<20), logger))
+	r.POST("/", G(func(ctx context.Context, req Request) (string, error) {
+		return req.Name, nil
+	}).String())
+
+	body := `{"name":"gopher"}`
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if logged != body {
+		t.Errorf("expected logged body %s, got %s", body, logged)
+	}
+
+	if w.Body.String() != "gopher" {
+		t.Errorf("expected body %s, got %s", "gopher", w.Body.String())
+	}
+}
+
+func TestBufferBodyTooLarge(t *testing.T) {
+	handler := BufferBody(4)(func(w http.ResponseWriter, r *http.Request) error {
+		t.Error("handler should not be called")
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("too large"))
+	w := httptest.NewRecorder()
+
+	var maxBytesErr *http.MaxBytesError
+	if err := handler(w, req); !errors.As(err, &maxBytesErr) {
+		t.Errorf("expected *http.MaxBytesError, got %v", err)
+	}
+}
+
+func TestRequireContentType(t *testing.T) {
+	type Request struct {
+		Name string `json:"name"`
+	}
+
+	handler := RequireContentType("application/json")(G(func(ctx context.Context, req Request) (string, error) {
+		return req.Name, nil
+	}).String())
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<name>gopher</name>"))
+	req.Header.Set("Content-Type", "application/xml")
+	w := httptest.NewRecorder()
+
+	if err := handler(w, req); !errors.Is(err, binding.ErrUnsupportedMediaType) {
+		t.Errorf("expected binding.ErrUnsupportedMediaType, got %v", err)
+	}
+
+	r := New()
+	r.POST("/", handler)
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("<name>gopher</name>"))
+	req.Header.Set("Content-Type", "application/xml")
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("expected status code %d, got %d", http.StatusUnsupportedMediaType, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	w = httptest.NewRecorder()
+
+	if err := handler(w, req); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+
+	if w.Body.String() != "gopher" {
+		t.Errorf("expected body %s, got %s", "gopher", w.Body.String())
+	}
+}
+
+func TestRedirectHTTPS(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	tests := []struct {
+		name     string
+		opts     RedirectHTTPSOptions
+		proto    string
+		status   int
+		location string
+	}{
+		{"default", RedirectHTTPSOptions{}, "", http.StatusPermanentRedirect, "https://example.com/path?q=1"},
+		{"custom", RedirectHTTPSOptions{Host: "secure.example.com", Port: "8443", StatusCode: http.StatusMovedPermanently}, "", http.StatusMovedPermanently, "https://secure.example.com:8443/path?q=1"},
+		{"forwarded", RedirectHTTPSOptions{}, "https", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "http://example.com:8080/path?q=1", nil)
+			if tt.proto != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.proto)
+			}
+			w := httptest.NewRecorder()
+
+			if err := RedirectHTTPS(tt.opts)(next)(w, req); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if w.Code != tt.status {
+				t.Errorf("expected status code %d, got %d", tt.status, w.Code)
+			}
+			if w.Header().Get("Location") != tt.location {
+				t.Errorf("expected location %q, got %q", tt.location, w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestDecompress(t *testing.T) {
+	type Request struct {
+		Name string `json:"name"`
+	}
+
+	r := New(WithMiddleware(Decompress()))
+	r.POST("/", G(func(ctx context.Context, req Request) (string, error) {
+		return req.Name, nil
+	}).String())
+
+	var body bytes.Buffer
+	zw := gzip.NewWriter(&body)
+	_, _ = zw.Write([]byte(`{"name":"gopher"}`))
+	_ = zw.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/", &body)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "gopher" {
+		t.Errorf("expected body %s, got %s", "gopher", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/", strings.NewReader("not gzip"))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Encoding", "gzip")
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+
+	r := New(WithMiddleware(Concurrency(2)))
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	codes := make(chan int, 4)
+	serve := func() {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		codes <- w.Code
+	}
+
+	// occupy both slots
+	go serve()
+	go serve()
+	<-started
+	<-started
+
+	// further requests are rejected while the slots are held
+	serve()
+	serve()
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusServiceUnavailable {
+			t.Errorf("expected status code %d, got %d", http.StatusServiceUnavailable, code)
+		}
+	}
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		if code := <-codes; code != http.StatusOK {
+			t.Errorf("expected status code %d, got %d", http.StatusOK, code)
+		}
+	}
+}
+
+func TestConcurrencyReleasesOnPanic(t *testing.T) {
+	r := New(WithMiddleware(Concurrency(1, WithConcurrencyWait(time.Second))))
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		panic("boom")
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("request %d: expected status code %d, got %d", i, http.StatusInternalServerError, w.Code)
+		}
+	}
+}
+
+func TestIdempotency(t *testing.T) {
+	calls := 0
+	r := New(WithMiddleware(Idempotency(NewMemoryIdempotencyStore())))
+	r.POST("/payments", func(w http.ResponseWriter, r *http.Request) error {
+		calls++
+		w.Header().Set("X-Payment", "p1")
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte("payment " + strconv.Itoa(calls)))
+		return err
+	})
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+		req.Header.Set("Idempotency-Key", key)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("abc")
+	second := send("abc")
+
+	if calls != 1 {
+		t.Errorf("expected handler to run once, ran %d times", calls)
+	}
+
+	if second.Code != http.StatusCreated || second.Body.String() != "payment 1" {
+		t.Errorf("expected replayed response, got %d %q", second.Code, second.Body.String())
+	}
+
+	if second.Header().Get("X-Payment") != "p1" || second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("expected replayed headers, got %v", second.Header())
+	}
+
+	if first.Header().Get("Idempotent-Replayed") != "" {
+		t.Error("expected first response not to be marked as replayed")
+	}
+
+	if w := send("other"); w.Body.String() != "payment 2" {
+		t.Errorf("expected a new key to run the handler, got %q", w.Body.String())
+	}
+}
+
+func TestIdempotencyScope(t *testing.T) {
+	calls := 0
+	scope := WithIdempotencyScope(func(r *http.Request) string { return r.Header.Get("X-User") })
+	r := New(WithMiddleware(Idempotency(NewMemoryIdempotencyStore(), scope)))
+	r.POST("/payments", func(w http.ResponseWriter, r *http.Request) error {
+		calls++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: r.Header.Get("X-User")})
+		_, err := w.Write([]byte("payment " + strconv.Itoa(calls)))
+		return err
+	})
+
+	send := func(user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/payments", nil)
+		req.Header.Set("Idempotency-Key", "abc")
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	send("alice")
+	if w := send("bob"); w.Body.String() != "payment 2" {
+		t.Errorf("expected another caller's key not to be replayed, got %q", w.Body.String())
+	}
+
+	replayed := send("alice")
+	if replayed.Body.String() != "payment 1" || replayed.Header().Get("Idempotent-Replayed") != "true" {
+		t.Errorf("expected the same caller to get the replay, got %q", replayed.Body.String())
+	}
+
+	if cookie := replayed.Header().Get("Set-Cookie"); cookie != "" {
+		t.Errorf("expected Set-Cookie not to be replayed, got %q", cookie)
+	}
+}
+
+func TestGzipSniffsContentType(t *testing.T) {
+	r := New(WithMiddleware(Gzip()))
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(http.StatusCreated)
+		_, err := w.Write([]byte("<!DOCTYPE html><html><body>hello</body></html>"))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated || w.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzipped 201, got %d %v", w.Code, w.Header())
+	}
+
+	if contentType := w.Header().Get("Content-Type"); contentType != "text/html; charset=utf-8" {
+		t.Errorf("expected the sniffed content type, got %q", contentType)
+	}
+}
+
+func TestGzipETag(t *testing.T) {
+	body := strings.Repeat("hello world ", 100)
+	r := New(WithMiddleware(Gzip(), ETag()))
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		return httpx.StringResponse{Data: body}.IntoResponse(w)
+	})
+
+	send := func(acceptEncoding, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		req.Header.Set("If-None-Match", ifNoneMatch)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	plain := send("", "")
+	etag := plain.Header().Get("ETag")
+	if etag == "" || plain.Body.String() != body {
+		t.Fatalf("expected an ETag and the plain body, got %q", etag)
+	}
+
+	compressed := send("gzip", "")
+	if compressed.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected gzip encoding, got %q", compressed.Header().Get("Content-Encoding"))
+	}
+
+	// the tag is computed over the uncompressed body, weakened for the compressed bytes
+	if compressed.Header().Get("ETag") != "W/"+etag {
+		t.Errorf("expected ETag %q, got %q", "W/"+etag, compressed.Header().Get("ETag"))
+	}
+
+	for _, w := range []*httptest.ResponseRecorder{plain, compressed} {
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("expected Vary header, got %q", w.Header().Get("Vary"))
+		}
+	}
+
+	reader, err := gzip.NewReader(compressed.Body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil || string(decoded) != body {
+		t.Errorf("expected decompressed body to match, got %v", err)
+	}
+
+	notModified := send("gzip", compressed.Header().Get("ETag"))
+	if notModified.Code != http.StatusNotModified || notModified.Body.Len() != 0 {
+		t.Errorf("expected empty 304, got %d with %d bytes", notModified.Code, notModified.Body.Len())
+	}
+
+	if notModified.Header().Get("Content-Encoding") != "" {
+		t.Errorf("expected no content encoding on 304, got %q", notModified.Header().Get("Content-Encoding"))
+	}
+}
+
+func TestCache(t *testing.T) {
+	calls := 0
+	r := New(WithMiddleware(Cache(NewMemoryCacheStore(), time.Minute)))
+	r.GET("/report", func(w http.ResponseWriter, r *http.Request) error {
+		calls++
+		w.Header().Set("Vary", "Accept-Language")
+		_, err := w.Write([]byte("report " + strconv.Itoa(calls)))
+		return err
+	})
+
+	send := func(language, cacheControl string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/report?year=2024", nil)
+		req.Header.Set("Accept-Language", language)
+		req.Header.Set("Cache-Control", cacheControl)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("en", "")
+	second := send("en", "")
+
+	if calls != 1 || second.Body.String() != "report 1" {
+		t.Errorf("expected second request to be served from cache, got %q after %d calls", second.Body.String(), calls)
+	}
+
+	if first.Header().Get("X-Cache") != "" || second.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("expected only the second response to be a cache hit")
+	}
+
+	if w := send("fr", ""); w.Body.String() != "report 2" {
+		t.Errorf("expected a different Accept-Language to miss the cache, got %q", w.Body.String())
+	}
+
+	if w := send("fr", "no-store"); w.Body.String() != "report 3" {
+		t.Errorf("expected no-store to bypass the cache, got %q", w.Body.String())
+	}
+}
+
+func TestCacheCredentials(t *testing.T) {
+	calls := 0
+	r := New(WithMiddleware(Cache(NewMemoryCacheStore(), time.Minute)))
+	r.GET("/me", func(w http.ResponseWriter, r *http.Request) error {
+		calls++
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: strconv.Itoa(calls)})
+		_, err := w.Write([]byte("user " + strconv.Itoa(calls)))
+		return err
+	})
+	r.GET("/news", func(w http.ResponseWriter, r *http.Request) error {
+		calls++
+		w.Header().Set("Cache-Control", "public, max-age=60")
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: strconv.Itoa(calls)})
+		_, err := w.Write([]byte("news " + strconv.Itoa(calls)))
+		return err
+	})
+
+	send := func(target, header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set(header, value)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	send("/me", "Authorization", "Bearer alice")
+	if w := send("/me", "Cookie", "session=bob"); w.Body.String() != "user 2" {
+		t.Errorf("expected a response to a credentialed request not to be cached, got %q", w.Body.String())
+	}
+
+	send("/news", "Cookie", "session=alice")
+	hit := send("/news", "Authorization", "Bearer bob")
+	if hit.Header().Get("X-Cache") != "HIT" || hit.Body.String() != "news 3" {
+		t.Errorf("expected a public response to be cached, got %q", hit.Body.String())
+	}
+
+	if cookie := hit.Header().Get("Set-Cookie"); cookie != "" {
+		t.Errorf("expected Set-Cookie not to be stored, got %q", cookie)
+	}
+}
+
+func TestMemoryStoreBounded(t *testing.T) {
+	var store memoryStore[int]
+	store.set("expired", 1, -time.Second, 3)
+	store.set("soon", 2, time.Minute, 3)
+	store.set("later", 3, time.Hour, 3)
+
+	store.set("new", 4, time.Hour, 3)
+	if _, ok := store.entries["expired"]; ok || len(store.entries) != 3 {
+		t.Errorf("expected the expired entry to be swept, got %v", store.entries)
+	}
+
+	store.set("newer", 5, time.Hour, 3)
+	if _, ok := store.entries["soon"]; ok || len(store.entries) != 3 {
+		t.Errorf("expected the entry closest to expiring to be evicted, got %v", store.entries)
+	}
+
+	cache := &MemoryCacheStore{MaxEntries: 2}
+	for i := range 10 {
+		_ = cache.Set(context.Background(), strconv.Itoa(i), &CachedResponse{}, time.Minute)
+	}
+	if len(cache.store.entries) != 2 {
+		t.Errorf("expected the cache store to hold at most 2 entries, got %d", len(cache.store.entries))
+	}
+}
+
+func TestStripPrefix(t *testing.T) {
+	r := New(WithPreRouting(StripPrefix("/app")))
+	r.GET("/users", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("users at " + r.URL.Path))
+		return err
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app/users", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "users at /users" {
+		t.Errorf("expected /app/users to route to /users, got %d %q", w.Code, w.Body.String())
+	}
+
+	for _, target := range []string{"/users", "/application/users"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected status code %d for %s, got %d", http.StatusNotFound, target, w.Code)
+		}
+	}
+}
+
+func TestBodyLog(t *testing.T) {
+	var entries []BodyLogEntry
+	r := New(WithMiddleware(BodyLog(BodyLogOptions{
+		Redact: RedactJSONFields("password", "token"),
+		Sink:   func(entry BodyLogEntry) { entries = append(entries, entry) },
+	})))
+	r.POST("/login", func(w http.ResponseWriter, r *http.Request) error {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if string(body) != `{"user":"gopher","password":"secret"}` {
+			t.Errorf("expected the handler to receive the full body, got %q", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, err = w.Write([]byte(`{"token":"abc123","user":"gopher"}`))
+		return err
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"user":"gopher","password":"secret"}`))
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != `{"token":"abc123","user":"gopher"}` {
+		t.Errorf("expected the client to receive the full response, got %q", w.Body.String())
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log entry, got %d", len(entries))
+	}
+
+	entry := entries[0]
+	if string(entry.RequestBody) != `{"password":"[REDACTED]","user":"gopher"}` {
+		t.Errorf("unexpected logged request body %q", entry.RequestBody)
+	}
+
+	if string(entry.ResponseBody) != `{"token":"[REDACTED]","user":"gopher"}` {
+		t.Errorf("unexpected logged response body %q", entry.ResponseBody)
+	}
+
+	if entry.StatusCode != http.StatusOK {
+		t.Errorf("expected logged status code %d, got %d", http.StatusOK, entry.StatusCode)
+	}
+}
+
+func TestCORSCredentialsAnyOrigin(t *testing.T) {
+	defer func() {
+		if p := recover(); p == nil {
+			t.Error("expected CORS to panic when credentials are allowed for any origin")
+		}
+	}()
+	CORS(CORSOptions{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+}
+
+func TestCORSPreflight(t *testing.T) {
+	called := false
+	r := New(WithPreRouting(CORS(CORSOptions{
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPost},
+		MaxAge:         10 * time.Minute,
+	})))
+	r.POST("/users", G(func(ctx context.Context, req struct {
+		Name string `json:"name"`
+	}) (string, error) {
+		called = true
+		return req.Name, nil
+	}).String())
+
+	req := httptest.NewRequest(http.MethodOptions, "/users", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if called {
+		t.Error("expected the handler not to run for a preflight request")
+	}
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status code %d, got %d", http.StatusNoContent, w.Code)
+	}
+
+	expected := map[string]string{
+		"Access-Control-Allow-Origin":  "https://app.example.com",
+		"Access-Control-Allow-Methods": "GET, POST",
+		"Access-Control-Allow-Headers": "Content-Type",
+		"Access-Control-Max-Age":       "600",
+	}
+	for name, value := range expected {
+		if got := w.Header().Get(name); got != value {
+			t.Errorf("expected %s %q, got %q", name, value, got)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if !called || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("expected the actual request to reach the handler with CORS headers, got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestPreload(t *testing.T) {
+	r := New()
+	r.GET("/", Preload(
+		PreloadLink{URL: "/style.css", As: "style"},
+		PreloadLink{URL: "/inter.woff2", As: "font", Type: "font/woff2", CrossOrigin: true},
+	)(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("<html></html>"))
+		return err
+	}))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	expected := []string{
+		"</style.css>; rel=preload; as=style",
+		`</inter.woff2>; rel=preload; as=font; type="font/woff2"; crossorigin`,
+	}
+	links := w.Header().Values("Link")
+	if len(links) != len(expected) {
+		t.Fatalf("expected %d Link headers, got %v", len(expected), links)
+	}
+	for i, link := range links {
+		if link != expected[i] {
+			t.Errorf("expected Link %q, got %q", expected[i], link)
+		}
+	}
+}
+
+func TestRequireHeader(t *testing.T) {
+	r := New(WithMiddleware(
+		RequireHeader("X-Api-Version", func(v string) bool { return v == "1" || v == "2" }),
+		RequireHeader("X-Client", nil),
+	))
+	r.GET("/users", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("users"))
+		return err
+	})
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"valid", map[string]string{"X-Api-Version": "2", "X-Client": "cli"}, http.StatusOK},
+		{"missing", map[string]string{"X-Client": "cli"}, http.StatusBadRequest},
+		{"invalid", map[string]string{"X-Api-Version": "3", "X-Client": "cli"}, http.StatusBadRequest},
+		{"missing second", map[string]string{"X-Api-Version": "1"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		for name, value := range tt.headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status code %d, got %d", tt.name, tt.status, w.Code)
+		}
+	}
+}
+
+// envelopeSerializer is a JSON serializer wrapping every encoded value in {"legacy": ...}.
+type envelopeSerializer struct {
+	decoded *int
+}
+
+func (e envelopeSerializer) Serialize(v any, w io.Writer) error {
+	return json.NewEncoder(w).Encode(map[string]any{"legacy": v})
+}
+
+func (e envelopeSerializer) Deserialize(r io.Reader, v any) error {
+	*e.decoded++
+	return json.NewDecoder(r).Decode(v)
+}
+
+func TestWithSerializer(t *testing.T) {
+	decoded := 0
+	legacy := WithSerializer(envelopeSerializer{decoded: &decoded})
+	perTenant := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Header.Get("X-Tenant") == "legacy" {
+				return legacy(next)(w, r)
+			}
+			return next(w, r)
+		}
+	}
+
+	type Greeting struct {
+		Name string `json:"name"`
+	}
+	r := New(WithMiddleware(perTenant))
+	r.POST("/greet", G(func(ctx context.Context, req Greeting) (Greeting, error) {
+		return req, nil
+	}).JSON())
+
+	send := func(tenant string) string {
+		req := httptest.NewRequest(http.MethodPost, "/greet", strings.NewReader(`{"name":"gopher"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Tenant", tenant)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return strings.TrimSpace(w.Body.String())
+	}
+
+	if body := send("legacy"); body != `{"legacy":{"name":"gopher"}}` || decoded != 1 {
+		t.Errorf("expected the legacy serializer, got %s after %d decodes", body, decoded)
+	}
+
+	if body := send("modern"); body != `{"name":"gopher"}` || decoded != 1 {
+		t.Errorf("expected the global serializer, got %s after %d decodes", body, decoded)
+	}
+}
@@ -2,11 +2,25 @@
 package hx
 
 import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
-	"io/fs"
+	"mime"
+	"net"
 	"net/http"
 	"path"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/eatmoreapple/hx/binding"
+	"github.com/eatmoreapple/hx/httpx"
 )
 
 // Router is the main router structure that handles HTTP request routing and error handling.
@@ -17,18 +31,170 @@ type Router struct {
 	ErrHandler ErrorHandler
 
 	// mux is the underlying HTTP request multiplexer
-	mux *http.ServeMux
+	mux Mux
 
 	// basePath is the base path for all routes in this router
 	basePath string
 
 	// middleware stack for this router
 	middleware []Middleware
+
+	// errorStatuses maps errors to status codes used by the default error handler
+	errorStatuses []errorStatus
+
+	// routes records every route registered through this router and its groups
+	routes *routeTable
+
+	// recover converts panics in handlers into errors passed to ErrHandler
+	recover bool
+
+	// beforeRender hooks run before the handler of every route writes its response
+	beforeRender []func(w http.ResponseWriter, r *http.Request)
+
+	// metrics holds the hooks measuring requests, shared with groups
+	metrics *metricsHooks
+
+	// preRouting middleware wraps the multiplexer, see WithPreRouting
+	preRouting []Middleware
+
+	// dispatch routes requests through the preRouting middleware, nil when there is none
+	dispatch HandlerFunc
+}
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	Method  string      // HTTP method, e.g. "GET"
+	Pattern string      // Full path pattern including the base path, e.g. "/api/users/{id}"
+	Handler HandlerFunc // Handler as registered, before the middleware stack is applied
+}
+
+// routeTable is the list of registered routes shared by a router and its groups.
+type routeTable struct {
+	mu     sync.RWMutex
+	routes []RouteInfo
+}
+
+// add records a route.
+func (t *routeTable) add(route RouteInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.routes = append(t.routes, route)
+}
+
+// list returns a copy of the registered routes in registration order.
+func (t *routeTable) list() []RouteInfo {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return append([]RouteInfo(nil), t.routes...)
+}
+
+// errorStatus associates a target error with the status code the default error handler responds with.
+// Errors of a class rather than a single value are matched by match instead of target.
+type errorStatus struct {
+	target error
+	match  func(error) bool
+	status int
+}
+
+// matches reports whether err is handled by the mapping.
+func (e errorStatus) matches(err error) bool {
+	if e.match != nil {
+		return e.match(err)
+	}
+	return errors.Is(err, e.target)
+}
+
+// errorStatusesKey is the context key under which serve stores the router's error mappings.
+type errorStatusesKey struct{}
+
+// errorStatusFor returns the status the router serving ctx maps err to, using the default
+// mappings outside of a router, and false if no mapping matches.
+func errorStatusFor(ctx context.Context, err error) (int, bool) {
+	statuses, ok := ctx.Value(errorStatusesKey{}).([]errorStatus)
+	if !ok {
+		statuses = defaultErrorStatuses
+	}
+	for _, mapping := range statuses {
+		if mapping.matches(err) {
+			return mapping.status, true
+		}
+	}
+	return 0, false
+}
+
+// defaultErrorStatuses are the mappings the default error handler starts with.
+var defaultErrorStatuses = []errorStatus{
+	{target: context.Canceled, status: StatusClientClosedRequest},
+	{target: context.DeadlineExceeded, status: http.StatusGatewayTimeout},
+	{target: ErrMalformedBody, status: http.StatusBadRequest},
+	{target: ErrValidation, status: http.StatusBadRequest},
+	{target: ErrInvalidHeader, status: http.StatusBadRequest},
+	{target: httpx.ErrInvalidPathValue, status: http.StatusBadRequest},
+	{target: ErrOverloaded, status: http.StatusServiceUnavailable},
+	{target: binding.ErrMissingField, status: http.StatusUnprocessableEntity},
+	{match: isSchemaError, status: http.StatusUnprocessableEntity},
+	{target: binding.ErrUnsupportedMediaType, status: http.StatusUnsupportedMediaType},
+	{target: binding.ErrTooManyFields, status: http.StatusBadRequest},
+	{match: isMalformedInput, status: http.StatusBadRequest},
+	{match: isBodyTooLarge, status: http.StatusRequestEntityTooLarge},
+}
+
+// isBodyTooLarge reports whether err is a request body rejected for exceeding its size limit,
+// as returned by BufferBody, RawBody and SchemaBinder.
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+// isSchemaError reports whether err is a request body rejected by a schema.
+func isSchemaError(err error) bool {
+	var schemaErr *binding.SchemaError
+	return errors.As(err, &schemaErr)
+}
+
+// isMalformedInput reports whether err is a binding error caused by input that could not be
+// decoded or converted, such as invalid JSON or a non-numeric value for an int field.
+// Only errors wrapped in a BindError qualify, so decoding failures returned by handlers are not
+// mistaken for client errors.
+func isMalformedInput(err error) bool {
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		return false
+	}
+	var (
+		fieldErr  *binding.FieldError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		xmlErr    *xml.SyntaxError
+	)
+	return errors.As(bindErr, &fieldErr) || errors.As(bindErr, &syntaxErr) ||
+		errors.As(bindErr, &typeErr) || errors.As(bindErr, &xmlErr)
+}
+
+// StatusClientClosedRequest is the non-standard status code used when the client
+// closed the connection before the response was written.
+const StatusClientClosedRequest = 499
+
+// Mux is the request multiplexer routes are registered on. *http.ServeMux, the default,
+// implements it. Patterns use the http.ServeMux syntax, e.g. "GET /users/{id}",
+// so other implementations must translate them, including the wildcard names
+// read by path extractors through http.Request.PathValue.
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+	ServeHTTP(w http.ResponseWriter, r *http.Request)
 }
 
 // RouterOption defines a function type for configuring a Router instance.
 type RouterOption func(*Router)
 
+// WithMux sets the multiplexer routes are registered on, replacing the default http.ServeMux.
+// It must be passed before any option that registers routes.
+func WithMux(mux Mux) RouterOption {
+	return func(r *Router) {
+		r.mux = mux
+	}
+}
+
 // WithErrorHandler sets a custom error handler for the router.
 func WithErrorHandler(handler ErrorHandler) RouterOption {
 	return func(r *Router) {
@@ -36,6 +202,93 @@ func WithErrorHandler(handler ErrorHandler) RouterOption {
 	}
 }
 
+// ErrorRenderer is a function type that converts an error into a ResponseRender.
+// It is an alternative to ErrorHandler that follows the same rendering model as handlers,
+// leaving the actual writing of the response to the router.
+type ErrorRenderer func(r *http.Request, err error) httpx.ResponseRender
+
+// WithErrorRenderer sets a custom error renderer for the router.
+// The returned ResponseRender is rendered by the router in place of the error.
+// If the renderer returns nil, the default error handling is used.
+func WithErrorRenderer(renderer ErrorRenderer) RouterOption {
+	return func(router *Router) {
+		router.ErrHandler = func(w http.ResponseWriter, r *http.Request, err error) {
+			if render := renderer(r, err); render != nil {
+				_ = render.IntoResponse(w)
+				return
+			}
+			router.defaultErrorHandler(w, r, err)
+		}
+	}
+}
+
+// NegotiatedErrorHandler sets an error renderer that picks the error format from the Accept header.
+// Requests that explicitly accept text/html, as browsers do, are rendered with htmlRender,
+// while all other requests, including those accepting only wildcards, are rendered with jsonRender.
+// If the selected renderer is nil or returns nil, the default error handling is used.
+func NegotiatedErrorHandler(jsonRender, htmlRender func(err error) httpx.ResponseRender) RouterOption {
+	return WithErrorRenderer(func(r *http.Request, err error) httpx.ResponseRender {
+		render := jsonRender
+		if acceptsHTML(r) {
+			render = htmlRender
+		}
+		if render == nil {
+			return nil
+		}
+		return render(err)
+	})
+}
+
+// acceptsHTML reports whether the Accept header explicitly lists text/html with a non-zero quality.
+func acceptsHTML(r *http.Request) bool {
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != "text/html" {
+			continue
+		}
+		if q, err := strconv.ParseFloat(params["q"], 64); err == nil && q == 0 {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// WithErrorStatus makes the default error handler respond with status
+// for any error matching target according to errors.Is.
+// Mappings registered later take precedence over earlier ones and over the defaults,
+// which map context.Canceled to StatusClientClosedRequest,
+// context.DeadlineExceeded to 504 Gateway Timeout, ErrMalformedBody, ErrValidation, ErrInvalidHeader
+// and httpx.ErrInvalidPathValue to 400 Bad Request, and ErrOverloaded to 503 Service Unavailable.
+// Binding errors are mapped by class: binding.ErrMissingField and *binding.SchemaError to
+// 422 Unprocessable Entity, binding.ErrUnsupportedMediaType to 415 Unsupported Media Type,
+// and malformed input, i.e. *binding.FieldError, binding.ErrTooManyFields and JSON or XML
+// syntax and type errors, to 400 Bad Request.
+func WithErrorStatus(target error, status int) RouterOption {
+	return func(r *Router) {
+		r.errorStatuses = append([]errorStatus{{target: target, status: status}}, r.errorStatuses...)
+	}
+}
+
+// WithRecover controls whether panics raised by handlers are recovered by the router.
+// When enabled, which is the default, a panic is converted into an error wrapping ErrPanic
+// and passed to ErrHandler, so the default error handler responds with 500 Internal Server Error.
+// A panic with http.ErrAbortHandler is always re-raised to abort the response.
+func WithRecover(enabled bool) RouterOption {
+	return func(r *Router) {
+		r.recover = enabled
+	}
+}
+
+// WithBeforeRender adds a hook that runs for every request before the handler, its middleware,
+// or the error handler writes anything. It is typically used to set standard headers,
+// such as security headers, uniformly on both success and error responses.
+func WithBeforeRender(hook func(w http.ResponseWriter, r *http.Request)) RouterOption {
+	return func(r *Router) {
+		r.beforeRender = append(r.beforeRender, hook)
+	}
+}
+
 // WithMiddleware adds middleware to the router.
 func WithMiddleware(middleware ...Middleware) RouterOption {
 	return func(r *Router) {
@@ -43,24 +296,101 @@ func WithMiddleware(middleware ...Middleware) RouterOption {
 	}
 }
 
+// WithPreRouting adds middleware that ServeHTTP runs before the request is routed,
+// so unlike middleware added with Use it can change which route matches, e.g. StripPrefix.
+// Errors it returns are passed to ErrHandler; beforeRender hooks and panic recovery
+// only apply once a route has matched.
+func WithPreRouting(middleware ...Middleware) RouterOption {
+	return func(r *Router) {
+		r.preRouting = append(r.preRouting, middleware...)
+	}
+}
+
 // New creates a new Router instance with the given options.
 // If no error handler is provided, it uses a default one that returns 500 Internal Server Error.
 func New(options ...RouterOption) *Router {
 	r := &Router{
 		mux:      http.NewServeMux(),
 		basePath: "/",
-		ErrHandler: func(w http.ResponseWriter, r *http.Request, err error) {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-		},
+		routes:   &routeTable{},
+		recover:  true,
+		metrics:  &metricsHooks{},
+
+		errorStatuses: slices.Clone(defaultErrorStatuses),
 	}
+	r.ErrHandler = r.defaultErrorHandler
 
 	for _, opt := range options {
 		opt(r)
 	}
 
+	if len(r.preRouting) > 0 {
+		r.dispatch = Chain(r.preRouting...)(func(w http.ResponseWriter, req *http.Request) error {
+			r.mux.ServeHTTP(w, req)
+			return nil
+		})
+	}
+
 	return r
 }
 
+// defaultErrorHandler writes the error message with a 500 Internal Server Error status.
+// Errors registered with WithErrorStatus use their mapped status instead; for
+// StatusClientClosedRequest only the status is written since the client is gone.
+// Joined errors, such as those returned by Errors, are rendered as a JSON list of messages
+// even when they wrap mapped errors. Their status is the one mapped for the first joined error
+// that has one, e.g. 400 for Errors(fmt.Errorf("%w: name", ErrValidation), errTaken) whatever
+// errTaken maps to, then the one mapped for the joined error as a whole, and 500 otherwise.
+func (r *Router) defaultErrorHandler(w http.ResponseWriter, _ *http.Request, err error) {
+	if errs := unwrapJoined(err); len(errs) > 0 {
+		status, ok := 0, false
+		for _, e := range errs {
+			if status, ok = r.errorStatus(e); ok {
+				break
+			}
+		}
+		if !ok {
+			status, ok = r.errorStatus(err)
+		}
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		if status == StatusClientClosedRequest {
+			w.WriteHeader(status)
+			return
+		}
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Error()
+		}
+		_ = httpx.JSONResponse{
+			Data:       map[string][]string{"errors": messages},
+			StatusCode: status,
+		}.IntoResponse(w)
+		return
+	}
+	status, ok := r.errorStatus(err)
+	if !ok {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if status == StatusClientClosedRequest {
+		w.WriteHeader(status)
+		return
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// errorStatus returns the status mapped to err by the router's error mappings, and false if none matches.
+func (r *Router) errorStatus(err error) (int, bool) {
+	for _, mapping := range r.errorStatuses {
+		if mapping.matches(err) {
+			return mapping.status, true
+		}
+	}
+	return 0, false
+}
+
 // Group creates a new router group with the given path prefix.
 // All routes registered on the group will be prefixed with the group's path.
 // The group inherits the middleware stack from its parent.
@@ -70,6 +400,12 @@ func (r *Router) Group(prefix string) *Router {
 		basePath:   path.Join(r.basePath, prefix),
 		ErrHandler: r.ErrHandler,
 		middleware: append([]Middleware{}, r.middleware...),
+
+		errorStatuses: r.errorStatuses,
+		routes:        r.routes,
+		recover:       r.recover,
+		beforeRender:  r.beforeRender,
+		metrics:       r.metrics,
 	}
 }
 
@@ -81,27 +417,162 @@ func (r *Router) Use(middleware ...Middleware) {
 
 // Handle registers a new route with the given method and path.
 // The handler will be wrapped with the router's middleware stack.
+// Paths are normalized to start with a slash, except for host-qualified ServeMux patterns
+// such as "api.example.com/users", which only match requests for that host.
 func (r *Router) Handle(method, path string, handler HandlerFunc) {
+	r.handle(method, path, handler, r.middleware)
+}
+
+// handle registers a route like Handle, wrapping handler with the given middleware
+// instead of the router's stack.
+func (r *Router) handle(method, path string, handler HandlerFunc, middleware []Middleware) {
+	// Host-qualified patterns such as "example.com/path" keep their host in front
+	host, path := splitHost(path)
+
 	// Ensure path starts with /
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
 
 	// Combine base path with route path
-	fullPath := joinPath(r.basePath, path)
+	fullPath := host + joinPath(r.basePath, path)
 	pattern := fmt.Sprintf("%s %s", method, fullPath)
 
+	// Record the route before wrapping it
+	r.routes.add(RouteInfo{Method: method, Pattern: fullPath, Handler: handler})
+
 	// Apply middleware stack
+	if len(middleware) > 0 {
+		handler = Chain(middleware...)(handler)
+	}
+
+	// Register the route
+	r.mux.Handle(pattern, r.serve(handler))
+}
+
+// NotFound sets the handler for requests under the router's base path that match no route.
+// Called on a group, it only applies below the group prefix, so an API group can answer
+// unknown routes with JSON while the root router renders an HTML page:
+//
+//	r.NotFound(htmlNotFound)
+//	api := r.Group("/api")
+//	api.NotFound(jsonNotFound) // handles /api/unknown
+//
+// The most specific prefix wins. The handler runs through the middleware stack and error handler
+// like any route. Note that it also receives requests whose path exists under the prefix but
+// whose method is not registered, which would otherwise get 405 Method Not Allowed.
+func (r *Router) NotFound(handler HandlerFunc) {
+	pattern := joinPath(r.basePath, "/")
+	if !strings.HasSuffix(pattern, "/") {
+		pattern += "/"
+	}
 	if len(r.middleware) > 0 {
 		handler = Chain(r.middleware...)(handler)
 	}
+	r.mux.Handle(pattern, r.serve(handler))
+}
 
-	// Register the route
-	r.mux.HandleFunc(pattern, func(w http.ResponseWriter, req *http.Request) {
-		if err := handler(w, req); err != nil {
-			r.ErrHandler(w, req, err)
+// Fallback registers handler as the catch-all route of the whole router: it receives every
+// request, whatever its method, that no other pattern matches, such as requests to be proxied
+// to a legacy backend. It is registered on the ServeMux pattern "/", which has the lowest
+// precedence since ServeMux always prefers the most specific pattern, so regular routes,
+// groups, Static, Mount and NotFound prefixes all win over it. Called on a group, it still
+// covers the whole router rather than the group prefix.
+//
+// Unlike NotFound, which answers misses below a prefix, the fallback is a route: it is listed
+// by Routes with the method "*" and receives the request as routed, path and method intact.
+// Like NotFound it also receives requests whose path matches a route but whose method does not,
+// instead of 405 Method Not Allowed. Since both use the "/" pattern at the root, calling
+// NotFound on the root router and Fallback panics, like any duplicate ServeMux pattern.
+func (r *Router) Fallback(handler HandlerFunc) {
+	r.routes.add(RouteInfo{Method: "*", Pattern: "/", Handler: handler})
+	if len(r.middleware) > 0 {
+		handler = Chain(r.middleware...)(handler)
+	}
+	r.mux.Handle("/", r.serve(handler))
+}
+
+// serve adapts a handler into an http.HandlerFunc that recovers panics, runs the
+// beforeRender hooks, routes returned errors to the error handler, and reports the
+// request to the WithMetrics hooks. Errors caused by a disconnected client, such as a broken
+// pipe while writing the response, are not passed to the error handler.
+func (r *Router) serve(handler HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		var err error
+		if hooks := r.metrics.list(); len(hooks) > 0 {
+			sw := &statusResponseWriter{ResponseWriter: w}
+			w = sw
+			start := time.Now()
+			// registered before recoverPanic so it runs after the panic has been rendered
+			defer func() {
+				metrics := RequestMetrics{
+					Method:   req.Method,
+					Pattern:  req.Pattern,
+					Status:   cmp.Or(sw.status, http.StatusOK),
+					Duration: time.Since(start),
+					Err:      err,
+				}
+				for _, hook := range hooks {
+					hook(metrics)
+				}
+			}()
 		}
-	})
+		if r.recover {
+			defer r.recoverPanic(w, req)
+		}
+		// handlers resolve error statuses, e.g. for Result, with the same mappings as the error handler
+		req = req.WithContext(context.WithValue(req.Context(), errorStatusesKey{}, r.errorStatuses))
+		for _, hook := range r.beforeRender {
+			hook(w, req)
+		}
+		// a client that went away cannot receive an error response either
+		if err = handler(w, req); err != nil && !isClientGone(err) {
+			handleError(w, req, err, r.ErrHandler)
+		}
+	}
+}
+
+// isClientGone reports whether err is a write failure caused by the client closing the connection.
+func isClientGone(err error) bool {
+	return errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET) || errors.Is(err, net.ErrClosed)
+}
+
+// Routes returns all routes registered on the router and its groups, in registration order.
+func (r *Router) Routes() []RouteInfo {
+	return r.routes.list()
+}
+
+// Walk calls fn for every route registered on the router and its groups, in registration order.
+// The handler passed to fn is the one given at registration, before the middleware stack is applied.
+// This is useful for generating documentation or building per-route policy maps.
+func (r *Router) Walk(fn func(method, pattern string, h HandlerFunc)) {
+	for _, route := range r.Routes() {
+		fn(route.Method, route.Pattern, route.Handler)
+	}
+}
+
+// recoverPanic recovers a panic raised while handling req and passes it to ErrHandler.
+func (r *Router) recoverPanic(w http.ResponseWriter, req *http.Request) {
+	p := recover()
+	if p == nil {
+		return
+	}
+	if p == http.ErrAbortHandler {
+		panic(p)
+	}
+	r.ErrHandler(w, req, fmt.Errorf("%w: %v", ErrPanic, p))
+}
+
+// Match registers the same handler for each of the given methods on path.
+// Each registration composes with the middleware stack and base path like Handle.
+//
+// Example:
+//
+//	r.Match([]string{http.MethodPut, http.MethodPatch}, "/users/{id}", updateUser)
+func (r *Router) Match(methods []string, path string, handler HandlerFunc) {
+	for _, method := range methods {
+		r.Handle(method, path, handler)
+	}
 }
 
 // Common HTTP method handlers
@@ -142,50 +613,98 @@ func (r *Router) HEAD(path string, handler HandlerFunc) {
 	r.Handle(http.MethodHead, path, handler)
 }
 
-// Static registers a route to serve static files from the provided file system.
-// The pathPrefix is the URL path prefix to be stripped from the request URL.
-// The root is the file system to serve files from.
-//
-// Example:
-//
-//	r.Static("/assets", os.DirFS("./public/assets"))
-//
-// This will serve files from ./public/assets under the /assets URL path.
-// Request to /assets/js/main.js will serve ./public/assets/js/main.js.
-func (r *Router) Static(pathPrefix string, root fs.FS) {
-	// Ensure pathPrefix starts with /
-	if !strings.HasPrefix(pathPrefix, "/") {
-		pathPrefix = "/" + pathPrefix
-	}
-	// Ensure pathPrefix ends with / for subtree matching
-	if !strings.HasSuffix(pathPrefix, "/") {
-		pathPrefix = pathPrefix + "/"
-	}
+// HandleTimeout registers a route like Handle whose handler runs with a deadline d from the
+// start of the request, as if wrapped with the Timeout middleware. Handlers observing the
+// context return context.DeadlineExceeded once it passes, which the default error handler
+// turns into 504 Gateway Timeout.
+func (r *Router) HandleTimeout(method, path string, d time.Duration, handler HandlerFunc) {
+	r.Handle(method, path, Timeout(d)(handler))
+}
+
+// GETTimeout registers a new GET route with a per-route timeout, see HandleTimeout.
+func (r *Router) GETTimeout(path string, d time.Duration, handler HandlerFunc) {
+	r.HandleTimeout(http.MethodGet, path, d, handler)
+}
 
-	// Calculate the full path prefix to use with StripPrefix
-	// We need to know the full path including the router's base path
-	fullPath := joinPath(r.basePath, pathPrefix)
+// POSTTimeout registers a new POST route with a per-route timeout, see HandleTimeout.
+func (r *Router) POSTTimeout(path string, d time.Duration, handler HandlerFunc) {
+	r.HandleTimeout(http.MethodPost, path, d, handler)
+}
 
-	fileServer := http.FileServer(http.FS(root))
-	handlerToServe := http.StripPrefix(fullPath, fileServer)
+// anyMethods lists the standard methods registered by Any.
+var anyMethods = []string{
+	http.MethodGet,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodDelete,
+	http.MethodPatch,
+	http.MethodOptions,
+	http.MethodHead,
+}
 
-	handler := func(w http.ResponseWriter, req *http.Request) error {
-		handlerToServe.ServeHTTP(w, req)
-		return nil
-	}
+// Any registers the handler for all standard HTTP methods:
+// GET, POST, PUT, DELETE, PATCH, OPTIONS, and HEAD.
+// Each method is registered exactly once, so HEAD requests are served by an explicit
+// HEAD route rather than the implicit HEAD handling of GET routes.
+func (r *Router) Any(path string, handler HandlerFunc) {
+	r.Match(anyMethods, path, handler)
+}
+
+// Mount delegates every request under prefix to handler, with the prefix stripped from the URL path.
+// The handler is registered for all methods and does not run the router's middleware stack.
+//
+// Example:
+//
+//	r.Mount("/debug", http.DefaultServeMux)
+func (r *Router) Mount(prefix string, handler http.Handler) {
+	fullPath := strings.TrimSuffix(joinPath(r.basePath, prefix), "/")
+	r.mux.Handle(fullPath+"/", http.StripPrefix(fullPath, handler))
+}
 
-	// Register the handler with the router's middleware stack
-	// We use Handle directly but we need to pass the pathPrefix as is
-	// because Handle will combine it with basePath again.
-	r.Handle(http.MethodGet, pathPrefix, handler)
+// MountRouter composes an independent router under prefix.
+// Unlike Group, the sub-router shares nothing with r: requests under prefix are handled
+// by sub's own routes, middleware, and error handler, after the prefix has been stripped.
+//
+// Example:
+//
+//	admin := hx.New(hx.WithErrorHandler(adminErrors))
+//	admin.GET("/stats", stats)
+//	r.MountRouter("/admin", admin) // serves /admin/stats
+func (r *Router) MountRouter(prefix string, sub *Router) {
+	r.Mount(prefix, sub)
 }
 
 // ServeHTTP implements the http.Handler interface.
 // This method is called by the HTTP server to handle incoming requests.
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.dispatch != nil {
+		if err := r.dispatch(w, req); err != nil {
+			handleError(w, req, err, r.ErrHandler)
+		}
+		return
+	}
 	r.mux.ServeHTTP(w, req)
 }
 
+// splitHost splits a host-qualified ServeMux pattern such as "example.com/path" into its host
+// and path. A leading segment is treated as a host when it contains a dot or a port, or is
+// "localhost"; otherwise the host is empty and the pattern is returned as a plain path,
+// so "users/{id}" is still normalized to "/users/{id}".
+func splitHost(pattern string) (host, path string) {
+	if strings.HasPrefix(pattern, "/") {
+		return "", pattern
+	}
+	i := strings.IndexByte(pattern, '/')
+	if i <= 0 {
+		return "", pattern
+	}
+	candidate := pattern[:i]
+	if strings.ContainsAny(candidate, ".:") || candidate == "localhost" {
+		return candidate, pattern[i:]
+	}
+	return "", pattern
+}
+
 // joinPath joins two path segments ensuring there is exactly one slash between them.
 func joinPath(a, b string) string {
 	aslash := strings.HasSuffix(a, "/")
@@ -0,0 +1,186 @@
+package hx
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+// ControllerRoutes can be implemented by a controller to set the route of some of its methods
+// explicitly instead of deriving it from the method name. Routes maps a method name to a
+// "METHOD /path" pattern relative to the controller prefix, which allows path parameters:
+//
+//	func (c *UserController) Routes() map[string]string {
+//	    return map[string]string{"GetUser": "GET /users/{id}"}
+//	}
+type ControllerRoutes interface {
+	Routes() map[string]string
+}
+
+// controllerVerbs maps method name prefixes to the HTTP method they register.
+var controllerVerbs = []struct {
+	prefix string
+	method string
+}{
+	{"Get", http.MethodGet},
+	{"Post", http.MethodPost},
+	{"Put", http.MethodPut},
+	{"Patch", http.MethodPatch},
+	{"Delete", http.MethodDelete},
+	{"Head", http.MethodHead},
+	{"Options", http.MethodOptions},
+}
+
+var (
+	contextType = reflect.TypeFor[context.Context]()
+	errorType   = reflect.TypeFor[error]()
+)
+
+// Controller registers the exported methods of c as routes under prefix.
+// A method becomes a route when its signature is func(context.Context, Request) (Response, error),
+// where Request and Response follow the same rules as with G(...).JSON(): the request is extracted
+// or bound, and the response is rendered directly when it implements httpx.ResponseRender or
+// serialized as JSON otherwise. Methods with any other signature are ignored.
+//
+// The route is derived from the method name: a leading HTTP verb (Get, Post, Put, Patch, Delete,
+// Head or Options) selects the method, and the rest of the name, converted to kebab-case,
+// is the path. A method named after the verb alone is registered on prefix itself:
+//
+//	GetUser        -> GET    /prefix/user
+//	PostUserAvatar -> POST   /prefix/user-avatar
+//	Delete         -> DELETE /prefix
+//
+// Handler methods without a verb prefix, or that need path parameters, must be listed by
+// implementing ControllerRoutes. Controller panics if such a listed method does not exist
+// or does not have a handler signature.
+func (r *Router) Controller(prefix string, c any) {
+	group := r.Group(prefix)
+	value := reflect.ValueOf(c)
+
+	var overrides map[string]string
+	if routes, ok := c.(ControllerRoutes); ok {
+		overrides = routes.Routes()
+	}
+
+	for i := 0; i < value.NumMethod(); i++ {
+		name := value.Type().Method(i).Name
+		method := value.Method(i)
+
+		if pattern, ok := overrides[name]; ok {
+			if !isControllerMethod(method.Type()) {
+				panic(fmt.Sprintf("hx: controller method %s does not have a handler signature", name))
+			}
+			verb, path, _ := strings.Cut(pattern, " ")
+			group.Handle(verb, strings.TrimSpace(path), controllerHandler(method))
+			continue
+		}
+
+		if !isControllerMethod(method.Type()) {
+			continue
+		}
+		if verb, path, ok := controllerRoute(name); ok {
+			group.Handle(verb, path, controllerHandler(method))
+		}
+	}
+
+	for name := range overrides {
+		if _, ok := value.Type().MethodByName(name); !ok {
+			panic(fmt.Sprintf("hx: controller has no method %s", name))
+		}
+	}
+}
+
+// isControllerMethod reports whether t is func(context.Context, Request) (Response, error).
+func isControllerMethod(t reflect.Type) bool {
+	return t.NumIn() == 2 && t.In(0) == contextType &&
+		t.NumOut() == 2 && t.Out(1) == errorType
+}
+
+// controllerRoute derives the HTTP method and path from a controller method name.
+// It reports false when the name does not start with an HTTP verb.
+func controllerRoute(name string) (method, path string, ok bool) {
+	for _, verb := range controllerVerbs {
+		rest, found := strings.CutPrefix(name, verb.prefix)
+		if !found {
+			continue
+		}
+		// "Getaway" is not a GET route
+		if rest != "" && !unicode.IsUpper(rune(rest[0])) {
+			continue
+		}
+		return verb.method, "/" + kebabCase(rest), true
+	}
+	return "", "", false
+}
+
+// kebabCase converts a CamelCase name into kebab-case, keeping acronyms together: "UserID" -> "user-id".
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, c := range runes {
+		if unicode.IsUpper(c) {
+			startsWord := i > 0 && (!unicode.IsUpper(runes[i-1]) ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]))
+			if startsWord {
+				sb.WriteByte('-')
+			}
+			c = unicode.ToLower(c)
+		}
+		sb.WriteRune(c)
+	}
+	return sb.String()
+}
+
+// controllerHandler adapts a controller method into a HandlerFunc.
+// It mirrors TypedHandlerFunc.JSON using reflection, since the request type is only known at runtime.
+func controllerHandler(method reflect.Value) HandlerFunc {
+	requestType := method.Type().In(1)
+	isPointer := requestType.Kind() == reflect.Pointer
+	elemType := requestType
+	if isPointer {
+		elemType = requestType.Elem()
+	}
+
+	pointerType := reflect.PointerTo(elemType)
+	isDecoder := pointerType.Implements(requestDecoderType)
+	isExtractor := httpx.IsRequestExtractorType(pointerType)
+	hasEnricher := containsContextEnricher(requestType, make(map[reflect.Type]struct{}))
+
+	return func(w http.ResponseWriter, r *http.Request) error {
+		target := reflect.New(elemType)
+
+		var err error
+		if isExtractor && !isDecoder {
+			err = target.Interface().(httpx.RequestExtractor).FromRequest(r)
+		} else {
+			err = ShouldBind(r, target.Interface())
+		}
+		if err != nil {
+			return bindError(err)
+		}
+		if hasEnricher {
+			r = r.WithContext(enrichContext(r.Context(), target, make(map[enrichVisit]struct{})))
+		}
+
+		request := target
+		if !isPointer {
+			request = target.Elem()
+		}
+		ctx := withStatusHolder(r.Context())
+		out := method.Call([]reflect.Value{reflect.ValueOf(ctx), request})
+		if err, _ := out[1].Interface().(error); err != nil {
+			return err
+		}
+
+		resp := out[0].Interface()
+		if render, ok := resp.(httpx.ResponseRender); ok {
+			return render.IntoResponse(w)
+		}
+		return httpx.JSONResponse{Data: resp, StatusCode: responseStatus(ctx, resp)}.IntoResponse(w)
+	}
+}
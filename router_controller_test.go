@@ -0,0 +1,105 @@
+package hx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+type userController struct{}
+
+func (userController) GetUser(ctx context.Context, req struct {
+	Name string `form:"name"`
+}) (map[string]string, error) {
+	return map[string]string{"name": req.Name}, nil
+}
+
+func (userController) PostUserAvatar(ctx context.Context, req *struct {
+	URL string `json:"url"`
+}) (httpx.StringResponse, error) {
+	return httpx.StringResponse{Data: req.URL, StatusCode: http.StatusCreated}, nil
+}
+
+type userID string
+
+func (userID) ValueName() string { return "id" }
+
+func (userController) FindUser(ctx context.Context, req struct {
+	ID httpx.FromPath[userID]
+}) (string, error) {
+	return string(req.ID.Value()), nil
+}
+
+// Helper has no handler signature and must be ignored.
+func (userController) Helper() string { return "" }
+
+func (userController) Routes() map[string]string {
+	return map[string]string{"FindUser": "GET /users/{id}"}
+}
+
+func TestRouterController(t *testing.T) {
+	r := New()
+	r.Controller("/api", userController{})
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+		status int
+		want   string
+	}{
+		{http.MethodGet, "/api/user?name=alice", "", http.StatusOK, "{\"name\":\"alice\"}\n"},
+		{http.MethodPost, "/api/user-avatar", `{"url":"a.png"}`, http.StatusCreated, "a.png"},
+		{http.MethodGet, "/api/users/42", "", http.StatusOK, "\"42\"\n"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		if tt.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s: expected status code %d, got %d", tt.method, tt.target, tt.status, w.Code)
+		}
+
+		if w.Body.String() != tt.want {
+			t.Errorf("%s %s: expected body %q, got %q", tt.method, tt.target, tt.want, w.Body.String())
+		}
+	}
+
+	if routes := r.Routes(); len(routes) != 3 {
+		t.Errorf("expected 3 routes, got %d", len(routes))
+	}
+}
+
+func TestControllerRoute(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		ok     bool
+	}{
+		{"GetUser", http.MethodGet, "/user", true},
+		{"PostUserAvatar", http.MethodPost, "/user-avatar", true},
+		{"DeleteUserID", http.MethodDelete, "/user-id", true},
+		{"GetHTTPStatus", http.MethodGet, "/http-status", true},
+		{"Patch", http.MethodPatch, "/", true},
+		{"Getaway", "", "", false},
+		{"FindUser", "", "", false},
+	}
+
+	for _, tt := range tests {
+		method, path, ok := controllerRoute(tt.name)
+		if method != tt.method || path != tt.path || ok != tt.ok {
+			t.Errorf("controllerRoute(%q) = %q, %q, %v; want %q, %q, %v", tt.name, method, path, ok, tt.method, tt.path, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package hx
+
+import (
+	"errors"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+// StaticOption defines a function type for configuring static file serving.
+type StaticOption func(*staticConfig)
+
+// staticConfig holds the settings applied by StaticOption.
+type staticConfig struct {
+	// notFound renders the response for missing files, nil uses the file server's plain 404
+	notFound httpx.ResponseRender
+
+	// precompressed serves .gz siblings to clients accepting gzip
+	precompressed bool
+
+	// skipMiddleware registers the route without the router's middleware stack
+	skipMiddleware bool
+}
+
+// middleware returns the middleware wrapping the routes registered by r with these settings.
+func (c staticConfig) middleware(r *Router) []Middleware {
+	if c.skipMiddleware {
+		return nil
+	}
+	return r.middleware
+}
+
+// WithStaticNotFound sets the response rendered when a requested static file does not exist,
+// e.g. a branded 404 page. The render is responsible for setting the status code.
+//
+// Example:
+//
+//	r.Static("/assets", assets, hx.WithStaticNotFound(httpx.HTMLResponse{
+//	    Template:   notFoundPage,
+//	    StatusCode: http.StatusNotFound,
+//	}))
+func WithStaticNotFound(render httpx.ResponseRender) StaticOption {
+	return func(c *staticConfig) {
+		c.notFound = render
+	}
+}
+
+// WithStaticPrecompressed serves precompressed siblings of static files.
+// When the client sends "Accept-Encoding: gzip" and a file named after the requested one with a
+// ".gz" suffix exists, e.g. app.js.gz for app.js, it is served with "Content-Encoding: gzip"
+// and the content type of the original file. Otherwise the uncompressed file is served.
+// Responses carry "Vary: Accept-Encoding" so caches keep both variants apart.
+func WithStaticPrecompressed() StaticOption {
+	return func(c *staticConfig) {
+		c.precompressed = true
+	}
+}
+
+// WithStaticSkipMiddleware registers the route without the router's middleware stack, so frequent
+// requests such as browsers fetching /favicon.ico skip authentication, logging and the like.
+// The router's error handling, WithRecover and WithMetrics still apply.
+//
+// Example:
+//
+//	r.Favicon(os.DirFS("./public"), hx.WithStaticSkipMiddleware())
+func WithStaticSkipMiddleware() StaticOption {
+	return func(c *staticConfig) {
+		c.skipMiddleware = true
+	}
+}
+
+// Static registers a route to serve static files from the provided file system.
+// The pathPrefix is the URL path prefix to be stripped from the request URL.
+// The root is the file system to serve files from.
+//
+// Example:
+//
+//	r.Static("/assets", os.DirFS("./public/assets"))
+//
+// This will serve files from ./public/assets under the /assets URL path.
+// Request to /assets/js/main.js will serve ./public/assets/js/main.js.
+//
+// Options such as WithStaticNotFound customize how the files are served.
+//
+// Conditional requests are supported: Last-Modified is set from the file's modification time
+// and a matching If-Modified-Since yields 304 Not Modified. File systems that report a zero
+// modification time, such as embed.FS, do not get Last-Modified headers.
+//
+// Range requests are supported as well, so large media files can be streamed and seeked:
+// a request with "Range: bytes=0-1023" receives 206 Partial Content with a Content-Range header.
+func (r *Router) Static(pathPrefix string, root fs.FS, options ...StaticOption) {
+	// Ensure pathPrefix starts with /
+	if !strings.HasPrefix(pathPrefix, "/") {
+		pathPrefix = "/" + pathPrefix
+	}
+	// Ensure pathPrefix ends with / for subtree matching
+	if !strings.HasSuffix(pathPrefix, "/") {
+		pathPrefix = pathPrefix + "/"
+	}
+
+	// Calculate the full path prefix to use with StripPrefix
+	// We need to know the full path including the router's base path
+	fullPath := joinPath(r.basePath, pathPrefix)
+
+	config := staticConfig{}
+	for _, opt := range options {
+		opt(&config)
+	}
+
+	fileServer := http.FileServer(http.FS(root))
+	handlerToServe := http.StripPrefix(fullPath, fileServer)
+
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		name := strings.TrimPrefix(req.URL.Path, fullPath)
+		if config.notFound != nil && !staticExists(root, name) {
+			return config.notFound.IntoResponse(w)
+		}
+		if config.precompressed {
+			w.Header().Add("Vary", "Accept-Encoding")
+			if serveGzipped(w, req, root, name) {
+				return nil
+			}
+		}
+		handlerToServe.ServeHTTP(w, req)
+		return nil
+	}
+
+	// Register the handler with the router's middleware stack
+	// We use Handle directly but we need to pass the pathPrefix as is
+	// because Handle will combine it with basePath again.
+	r.handle(http.MethodGet, pathPrefix, handler, config.middleware(r))
+}
+
+// StaticMulti registers a route serving static files looked up in several file systems.
+// For each request the sources are tried in order and the first one containing the file serves it,
+// so a runtime overrides directory listed first shadows embedded defaults listed after it:
+//
+//	r.StaticMulti("/assets", os.DirFS("./overrides"), embeddedAssets)
+//
+// Directories are served from the first source containing them, without merging their entries.
+func (r *Router) StaticMulti(pathPrefix string, sources ...fs.FS) {
+	r.Static(pathPrefix, layeredFS(sources))
+}
+
+// layeredFS is an fs.FS that opens each name from the first of its layers containing it.
+type layeredFS []fs.FS
+
+// Open implements fs.FS.
+func (l layeredFS) Open(name string) (fs.File, error) {
+	for _, layer := range l {
+		file, err := layer.Open(name)
+		if err == nil {
+			return file, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Favicon registers /favicon.ico served from the favicon.ico file at the root of fsys.
+// Like any route it is registered under the router's base path, so it is usually called on the
+// root router. Responses are cached by clients for a day; WithStaticSkipMiddleware keeps these
+// frequent browser requests out of the middleware stack.
+//
+// Example:
+//
+//	r.Favicon(os.DirFS("./public"))
+func (r *Router) Favicon(fsys fs.FS, options ...StaticOption) {
+	config := staticConfig{}
+	for _, opt := range options {
+		opt(&config)
+	}
+	r.handle(http.MethodGet, "/favicon.ico", func(w http.ResponseWriter, req *http.Request) error {
+		w.Header().Set("Content-Type", "image/x-icon")
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		http.ServeFileFS(w, req, fsys, "favicon.ico")
+		return nil
+	}, config.middleware(r))
+}
+
+// Robots registers /robots.txt responding with the given content as text/plain,
+// using the charset set by httpx.SetDefaultCharset.
+// Like Favicon, the route is registered under the router's base path and accepts WithStaticSkipMiddleware.
+//
+// Example:
+//
+//	r.Robots("User-agent: *\nDisallow: /admin/\n")
+func (r *Router) Robots(content string, options ...StaticOption) {
+	config := staticConfig{}
+	for _, opt := range options {
+		opt(&config)
+	}
+	r.handle(http.MethodGet, "/robots.txt", func(w http.ResponseWriter, req *http.Request) error {
+		w.Header().Set("Cache-Control", "public, max-age=86400")
+		return httpx.StringResponse{Data: content}.IntoResponse(w)
+	}, config.middleware(r))
+}
+
+// staticExists reports whether the request path, relative to the static prefix, names an entry in root.
+func staticExists(root fs.FS, name string) bool {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		name = "."
+	}
+	_, err := fs.Stat(root, name)
+	return err == nil
+}
+
+// serveGzipped serves the ".gz" sibling of name when the client accepts gzip and the sibling exists.
+// It reports whether the response was written.
+func serveGzipped(w http.ResponseWriter, req *http.Request, root fs.FS, name string) bool {
+	if !acceptsGzip(req) || name == "" || strings.HasSuffix(name, "/") {
+		return false
+	}
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	info, err := fs.Stat(root, name+".gz")
+	if err != nil || info.IsDir() {
+		return false
+	}
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Encoding", "gzip")
+	http.ServeFileFS(w, req, root, name+".gz")
+	return true
+}
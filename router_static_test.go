@@ -5,7 +5,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/eatmoreapple/hx/httpx"
 )
 
 func TestRouterStatic(t *testing.T) {
@@ -54,3 +59,282 @@ func TestRouterStatic(t *testing.T) {
 		t.Errorf("expected body %s, got %s", string(content), w.Body.String())
 	}
 }
+
+func TestRouterFavicon(t *testing.T) {
+	fsys := fstest.MapFS{"favicon.ico": {Data: []byte("icon")}}
+
+	r := New()
+	r.Favicon(fsys)
+
+	req := httptest.NewRequest(http.MethodGet, "/favicon.ico", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "image/x-icon" {
+		t.Errorf("expected content type %s, got %s", "image/x-icon", w.Header().Get("Content-Type"))
+	}
+
+	if w.Body.String() != "icon" {
+		t.Errorf("expected body %s, got %s", "icon", w.Body.String())
+	}
+}
+
+func TestRouterRobots(t *testing.T) {
+	content := "User-agent: *\nDisallow:\n"
+
+	r := New()
+	r.Robots(content)
+
+	req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Errorf("expected content type %s, got %s", "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	}
+
+	if w.Body.String() != content {
+		t.Errorf("expected body %q, got %q", content, w.Body.String())
+	}
+}
+
+func TestRouterFaviconMiddleware(t *testing.T) {
+	fsys := fstest.MapFS{"favicon.ico": {Data: []byte("icon")}}
+	marker := func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Set("X-Middleware", "1")
+			return next(w, r)
+		}
+	}
+
+	r := New()
+	r.Use(marker)
+	r.Favicon(fsys)
+	r.Group("/app").Favicon(fsys, WithStaticSkipMiddleware())
+
+	tests := []struct {
+		path       string
+		middleware string
+	}{
+		{"/favicon.ico", "1"},
+		{"/app/favicon.ico", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "icon" {
+			t.Errorf("%s: expected icon with status %d, got %d %q", tt.path, http.StatusOK, w.Code, w.Body.String())
+		}
+		if got := w.Header().Get("X-Middleware"); got != tt.middleware {
+			t.Errorf("%s: expected X-Middleware %q, got %q", tt.path, tt.middleware, got)
+		}
+	}
+}
+
+func TestRouterRobotsDefaultCharset(t *testing.T) {
+	defer httpx.SetDefaultCharset(httpx.DefaultCharset)
+	httpx.SetDefaultCharset("iso-8859-1")
+
+	r := New()
+	r.Robots("User-agent: *\n")
+
+	req := httptest.NewRequest(http.MethodGet, "/robots.txt", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); got != "text/plain; charset=iso-8859-1" {
+		t.Errorf("expected content type %s, got %s", "text/plain; charset=iso-8859-1", got)
+	}
+}
+
+func TestRouterStaticIfModifiedSince(t *testing.T) {
+	tmpDir := t.TempDir()
+	file := filepath.Join(tmpDir, "app.js")
+	if err := os.WriteFile(file, []byte("console.log(1)"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := os.Chtimes(file, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+
+	r := New()
+	r.Static("/static", os.DirFS(tmpDir))
+
+	tests := []struct {
+		ifModifiedSince time.Time
+		status          int
+	}{
+		{modTime, http.StatusNotModified},
+		{modTime.Add(-time.Hour), http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/static/app.js", nil)
+		req.Header.Set("If-Modified-Since", tt.ifModifiedSince.Format(http.TimeFormat))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("expected status code %d for If-Modified-Since %s, got %d", tt.status, tt.ifModifiedSince, w.Code)
+		}
+		if w.Header().Get("Last-Modified") != modTime.Format(http.TimeFormat) {
+			t.Errorf("expected Last-Modified %s, got %s", modTime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+		}
+	}
+}
+
+func TestRouterStaticNotFound(t *testing.T) {
+	fsys := fstest.MapFS{"app.js": {Data: []byte("console.log(1)")}}
+
+	r := New()
+	r.Static("/static", fsys, WithStaticNotFound(httpx.StringResponse{
+		Data:       "asset not found",
+		StatusCode: http.StatusNotFound,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/static/missing.js", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+
+	if w.Body.String() != "asset not found" {
+		t.Errorf("expected body %s, got %s", "asset not found", w.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/static/app.js", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestRouterStaticRange(t *testing.T) {
+	fsys := fstest.MapFS{"video.mp4": {Data: []byte("0123456789")}}
+
+	r := New()
+	r.Static("/media", fsys)
+
+	req := httptest.NewRequest(http.MethodGet, "/media/video.mp4", nil)
+	req.Header.Set("Range", "bytes=0-4")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusPartialContent {
+		t.Errorf("expected status code %d, got %d", http.StatusPartialContent, w.Code)
+	}
+
+	if w.Header().Get("Content-Range") != "bytes 0-4/10" {
+		t.Errorf("expected content range %s, got %s", "bytes 0-4/10", w.Header().Get("Content-Range"))
+	}
+
+	if w.Body.String() != "01234" {
+		t.Errorf("expected body %s, got %s", "01234", w.Body.String())
+	}
+}
+
+func TestRouterStaticPrecompressed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.js":    {Data: []byte("console.log(1)")},
+		"app.js.gz": {Data: []byte("gzipped")},
+		"style.css": {Data: []byte("body{}")},
+	}
+
+	r := New()
+	r.Static("/static", fsys, WithStaticPrecompressed())
+
+	tests := []struct {
+		target   string
+		encoding string
+		body     string
+		gzipped  bool
+	}{
+		{"/static/app.js", "gzip, deflate", "gzipped", true},
+		{"/static/app.js", "", "console.log(1)", false},
+		{"/static/app.js", "gzip;q=0", "console.log(1)", false},
+		{"/static/style.css", "gzip", "body{}", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("Accept-Encoding", tt.encoding)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tt.body {
+			t.Errorf("%s with %q: expected body %q, got %q", tt.target, tt.encoding, tt.body, w.Body.String())
+		}
+
+		if gzipped := w.Header().Get("Content-Encoding") == "gzip"; gzipped != tt.gzipped {
+			t.Errorf("%s with %q: expected gzip encoding %v, got %v", tt.target, tt.encoding, tt.gzipped, gzipped)
+		}
+
+		if tt.target == "/static/app.js" && !strings.HasPrefix(w.Header().Get("Content-Type"), "text/javascript") {
+			t.Errorf("%s with %q: expected javascript content type, got %s", tt.target, tt.encoding, w.Header().Get("Content-Type"))
+		}
+
+		if w.Header().Get("Vary") != "Accept-Encoding" {
+			t.Errorf("%s with %q: expected Vary header, got %q", tt.target, tt.encoding, w.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestRouterStaticMulti(t *testing.T) {
+	overrides := fstest.MapFS{"app.css": {Data: []byte("override")}}
+	defaults := fstest.MapFS{
+		"app.css": {Data: []byte("default")},
+		"app.js":  {Data: []byte("embedded")},
+	}
+
+	r := New()
+	r.StaticMulti("/assets", overrides, defaults)
+
+	tests := []struct {
+		target string
+		status int
+		body   string
+	}{
+		{"/assets/app.css", http.StatusOK, "override"},
+		{"/assets/app.js", http.StatusOK, "embedded"},
+		{"/assets/missing.js", http.StatusNotFound, "404 page not found\n"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status code %d, got %d", tt.target, tt.status, w.Code)
+		}
+
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.target, tt.body, w.Body.String())
+		}
+	}
+}
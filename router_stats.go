@@ -0,0 +1,165 @@
+package hx
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/eatmoreapple/hx/httpx"
+)
+
+// RequestMetrics describes a request handled by a route, as reported to WithMetrics hooks.
+type RequestMetrics struct {
+	Method   string        // Method of the request
+	Pattern  string        // Pattern of the matched route, e.g. "GET /users/{id}"
+	Status   int           // Status is the status code of the response
+	Duration time.Duration // Duration from routing to the end of the response, middleware included
+	Err      error         // Err is the error returned by the handler, nil after a panic
+}
+
+// metricsHooks is the list of hooks added with WithMetrics, shared by a router and its groups.
+type metricsHooks struct {
+	mu    sync.RWMutex
+	hooks []func(RequestMetrics)
+}
+
+// add appends hook to the list.
+func (m *metricsHooks) add(hook func(RequestMetrics)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, hook)
+}
+
+// list returns the hooks.
+func (m *metricsHooks) list() []func(RequestMetrics) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.hooks
+}
+
+// WithMetrics adds a hook called after every request handled by a route, with its pattern,
+// status code, duration and error, e.g. to feed an external metrics system.
+// Requests are only measured when at least one hook is registered.
+func WithMetrics(hook func(RequestMetrics)) RouterOption {
+	return func(r *Router) {
+		r.metrics.add(hook)
+	}
+}
+
+// statusResponseWriter records the status code written through it.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it to the underlying ResponseWriter.
+func (s *statusResponseWriter) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Write records the implicit 200 status and writes b to the underlying ResponseWriter.
+func (s *statusResponseWriter) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (s *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// statsWindow is the number of recent durations kept per route to compute latency percentiles.
+const statsWindow = 1024
+
+// RouteStats is the summary of a route reported by Router.Stats.
+type RouteStats struct {
+	Pattern  string  `json:"pattern"`
+	Requests int64   `json:"requests"`
+	Errors   int64   `json:"errors"`
+	P50      float64 `json:"p50_ms"`
+	P90      float64 `json:"p90_ms"`
+	P99      float64 `json:"p99_ms"`
+}
+
+// routeCounters accumulates the stats of a single route.
+type routeCounters struct {
+	requests  int64
+	errors    int64
+	durations [statsWindow]time.Duration
+}
+
+// statsCollector is the in-memory collector behind Router.Stats.
+type statsCollector struct {
+	mu     sync.Mutex
+	routes map[string]*routeCounters
+}
+
+// observe records a request.
+func (s *statsCollector) observe(m RequestMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counters, ok := s.routes[m.Pattern]
+	if !ok {
+		counters = &routeCounters{}
+		s.routes[m.Pattern] = counters
+	}
+	counters.durations[counters.requests%statsWindow] = m.Duration
+	counters.requests++
+	if m.Err != nil || m.Status >= http.StatusInternalServerError {
+		counters.errors++
+	}
+}
+
+// snapshot returns the stats of every route, sorted by pattern.
+func (s *statsCollector) snapshot() []RouteStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := make([]RouteStats, 0, len(s.routes))
+	for pattern, counters := range s.routes {
+		durations := slices.Clone(counters.durations[:min(counters.requests, statsWindow)])
+		slices.Sort(durations)
+		stats = append(stats, RouteStats{
+			Pattern:  pattern,
+			Requests: counters.requests,
+			Errors:   counters.errors,
+			P50:      percentile(durations, 50),
+			P90:      percentile(durations, 90),
+			P99:      percentile(durations, 99),
+		})
+	}
+	slices.SortFunc(stats, func(a, b RouteStats) int { return cmp.Compare(a.Pattern, b.Pattern) })
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted durations in milliseconds, using the nearest rank.
+func percentile(sorted []time.Duration, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := max((p*len(sorted)+99)/100, 1)
+	return float64(sorted[rank-1]) / float64(time.Millisecond)
+}
+
+// Stats enables an in-memory stats collector and registers a GET route at path reporting,
+// for every route, the number of requests, the number of errors, i.e. requests whose handler
+// returned an error or that got a 5xx status, and the 50th, 90th and 99th latency percentiles
+// in milliseconds over the last 1024 requests. Collection only starts once Stats is called.
+// The stats route is not protected: register it on a group with suitable middleware if needed.
+//
+// Example:
+//
+//	r.Group("/debug").Stats("/stats") // GET /debug/stats
+func (r *Router) Stats(path string) {
+	collector := &statsCollector{routes: make(map[string]*routeCounters)}
+	r.metrics.add(collector.observe)
+	r.GET(path, func(w http.ResponseWriter, req *http.Request) error {
+		return httpx.JSONResponse{Data: collector.snapshot()}.IntoResponse(w)
+	})
+}
@@ -1,10 +1,22 @@
 package hx
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"html/template"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/eatmoreapple/hx/binding"
+	"github.com/eatmoreapple/hx/httpx"
 )
 
 func TestRouter(t *testing.T) {
@@ -155,3 +167,723 @@ func TestJoinPath(t *testing.T) {
 		}
 	}
 }
+
+func TestRouterErrorRenderer(t *testing.T) {
+	r := New(WithErrorRenderer(func(r *http.Request, err error) httpx.ResponseRender {
+		return httpx.JSONResponse{
+			Data:       map[string]string{"error": err.Error()},
+			StatusCode: http.StatusBadRequest,
+		}
+	}))
+
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("bad input")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	if w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Errorf("expected json content type, got %s", w.Header().Get("Content-Type"))
+	}
+
+	if w.Body.String() != "{\"error\":\"bad input\"}\n" {
+		t.Errorf("expected body %q, got %q", "{\"error\":\"bad input\"}\n", w.Body.String())
+	}
+}
+
+func TestNegotiatedErrorHandler(t *testing.T) {
+	errorPage := template.Must(template.New("error").Parse("<h1>{{ . }}</h1>"))
+	r := New(NegotiatedErrorHandler(
+		func(err error) httpx.ResponseRender {
+			return httpx.JSONResponse{Data: map[string]string{"error": err.Error()}, StatusCode: http.StatusNotFound}
+		},
+		func(err error) httpx.ResponseRender {
+			return httpx.HTMLResponse{Data: err.Error(), StatusCode: http.StatusNotFound, Template: errorPage}
+		},
+	))
+
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("not found")
+	})
+
+	tests := []struct {
+		accept string
+		body   string
+	}{
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "<h1>not found</h1>"},
+		{"application/json", "{\"error\":\"not found\"}\n"},
+		{"*/*", "{\"error\":\"not found\"}\n"},
+		{"text/html;q=0, application/json", "{\"error\":\"not found\"}\n"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Accept %q: expected status code %d, got %d", tt.accept, http.StatusNotFound, w.Code)
+		}
+
+		if w.Body.String() != tt.body {
+			t.Errorf("Accept %q: expected body %q, got %q", tt.accept, tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestRouterAbort(t *testing.T) {
+	called := false
+	r := New(WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		called = true
+	}))
+
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			if r.Header.Get("Authorization") == "" {
+				return fmt.Errorf("auth: %w", Abort(httpx.JSONResponse{
+					Data:       map[string]string{"error": "unauthorized"},
+					StatusCode: http.StatusUnauthorized,
+				}))
+			}
+			return next(w, r)
+		}
+	})
+
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if called {
+		t.Error("expected error handler to be bypassed")
+	}
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+
+	if w.Body.String() != "{\"error\":\"unauthorized\"}\n" {
+		t.Errorf("expected body %q, got %q", "{\"error\":\"unauthorized\"}\n", w.Body.String())
+	}
+}
+
+type userIDParam string
+
+func (userIDParam) ValueName() string { return "id" }
+
+func TestRouterPathInt(t *testing.T) {
+	called := false
+	r := New()
+	r.GET("/user/{id}", G(func(ctx context.Context, req struct {
+		ID httpx.PathInt[userIDParam]
+	}) (int64, error) {
+		called = true
+		return req.ID.Value(), nil
+	}).JSON())
+
+	req := httptest.NewRequest(http.MethodGet, "/user/abc", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if called {
+		t.Error("expected handler not to run")
+	}
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/user/42", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "42\n" {
+		t.Errorf("expected body %q, got %q", "42\n", w.Body.String())
+	}
+}
+
+func TestRouterNotFound(t *testing.T) {
+	r := New()
+	r.NotFound(func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		_, err := w.Write([]byte("<h1>not found</h1>"))
+		return err
+	})
+	r.GET("/home", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	api := r.Group("/api")
+	api.NotFound(func(w http.ResponseWriter, r *http.Request) error {
+		return httpx.JSONResponse{Data: map[string]string{"error": "not found"}, StatusCode: http.StatusNotFound}.IntoResponse(w)
+	})
+	api.GET("/users", func(w http.ResponseWriter, r *http.Request) error {
+		return nil
+	})
+
+	tests := []struct {
+		target      string
+		status      int
+		contentType string
+	}{
+		{"/missing", http.StatusNotFound, "text/html; charset=utf-8"},
+		{"/api/missing", http.StatusNotFound, "application/json; charset=utf-8"},
+		{"/api/users/1", http.StatusNotFound, "application/json; charset=utf-8"},
+		{"/api/users", http.StatusOK, ""},
+		{"/home", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status code %d, got %d", tt.target, tt.status, w.Code)
+		}
+
+		if w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: expected content type %q, got %q", tt.target, tt.contentType, w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestRouterHostPattern(t *testing.T) {
+	r := New()
+	r.GET("api.example.com/users", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("api"))
+		return err
+	})
+	r.GET("users", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("default"))
+		return err
+	})
+
+	tests := []struct {
+		host string
+		body string
+	}{
+		{"api.example.com", "api"},
+		{"www.example.com", "default"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://"+tt.host+"/users", nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.host, tt.body, w.Body.String())
+		}
+	}
+
+	routes := r.Routes()
+	if routes[0].Pattern != "api.example.com/users" || routes[1].Pattern != "/users" {
+		t.Errorf("unexpected patterns %q and %q", routes[0].Pattern, routes[1].Pattern)
+	}
+}
+
+func TestRouterBindPathAndJSON(t *testing.T) {
+	type UpdateUser struct {
+		ID   int    `path:"id" json:"-"`
+		Name string `json:"name"`
+	}
+
+	r := New()
+	r.PUT("/users/{id}", G(func(ctx context.Context, req UpdateUser) (string, error) {
+		return fmt.Sprintf("%d:%s", req.ID, req.Name), nil
+	}).String())
+
+	req := httptest.NewRequest(http.MethodPut, "/users/42", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	if w.Body.String() != "42:gopher" {
+		t.Errorf("expected body %q, got %q", "42:gopher", w.Body.String())
+	}
+}
+
+func TestRouterHandleTimeout(t *testing.T) {
+	r := New()
+	handler := func(delay time.Duration) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			select {
+			case <-time.After(delay):
+				_, err := w.Write([]byte("done"))
+				return err
+			case <-r.Context().Done():
+				return r.Context().Err()
+			}
+		}
+	}
+	r.GETTimeout("/slow", 10*time.Millisecond, handler(time.Second))
+	r.GETTimeout("/fast", time.Second, handler(0))
+
+	tests := []struct {
+		target string
+		status int
+	}{
+		{"/slow", http.StatusGatewayTimeout},
+		{"/fast", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("%s: expected status code %d, got %d", tt.target, tt.status, w.Code)
+		}
+	}
+}
+
+func TestRouterContextErrors(t *testing.T) {
+	errTeapot := errors.New("teapot")
+
+	r := New(WithErrorStatus(errTeapot, http.StatusTeapot))
+	r.GET("/canceled", func(w http.ResponseWriter, r *http.Request) error {
+		return fmt.Errorf("query: %w", context.Canceled)
+	})
+	r.GET("/deadline", func(w http.ResponseWriter, r *http.Request) error {
+		return context.DeadlineExceeded
+	})
+	r.GET("/teapot", func(w http.ResponseWriter, r *http.Request) error {
+		return errTeapot
+	})
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/canceled", StatusClientClosedRequest},
+		{"/deadline", http.StatusGatewayTimeout},
+		{"/teapot", http.StatusTeapot},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("expected status code %d for %s, got %d", tt.status, tt.path, w.Code)
+		}
+	}
+}
+
+func TestRouterMatch(t *testing.T) {
+	r := New()
+	r.Match([]string{http.MethodPut, http.MethodPatch}, "/users/{id}", Warp(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Method))
+	}))
+
+	for _, method := range []string{http.MethodPut, http.MethodPatch} {
+		req := httptest.NewRequest(method, "/users/1", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Body.String() != method {
+			t.Errorf("expected body %s, got %s", method, w.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status code %d, got %d", http.StatusMethodNotAllowed, w.Code)
+	}
+}
+
+func TestRouterAny(t *testing.T) {
+	r := New()
+	r.Any("/proxy", Warp(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Method", r.Method)
+	}))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete, http.MethodHead, http.MethodOptions} {
+		req := httptest.NewRequest(method, "/proxy", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status code %d for %s, got %d", http.StatusOK, method, w.Code)
+		}
+		if w.Header().Get("X-Method") != method {
+			t.Errorf("expected method %s, got %s", method, w.Header().Get("X-Method"))
+		}
+	}
+}
+
+func TestRouterWalk(t *testing.T) {
+	r := New()
+	handler := Warp(func(w http.ResponseWriter, r *http.Request) {})
+
+	r.GET("/users", handler)
+	api := r.Group("/api")
+	api.POST("/items/{id}", handler)
+
+	type route struct{ method, pattern string }
+	var visited []route
+	r.Walk(func(method, pattern string, h HandlerFunc) {
+		if h == nil {
+			t.Errorf("expected handler for %s %s", method, pattern)
+		}
+		visited = append(visited, route{method, pattern})
+	})
+
+	expected := []route{
+		{http.MethodGet, "/users"},
+		{http.MethodPost, "/api/items/{id}"},
+	}
+	if len(visited) != len(expected) {
+		t.Fatalf("expected %d routes, got %d", len(expected), len(visited))
+	}
+	for i, rt := range visited {
+		if rt != expected[i] {
+			t.Errorf("expected route %d to be %v, got %v", i, expected[i], rt)
+		}
+	}
+}
+
+func TestRouterMountRouter(t *testing.T) {
+	r := New()
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			w.Header().Set("X-Parent", "true")
+			return next(w, r)
+		}
+	})
+
+	sub := New(WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("sub: " + err.Error()))
+	}))
+	sub.GET("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	})
+
+	r.MountRouter("/admin", sub)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/fail", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTeapot {
+		t.Errorf("expected status code %d, got %d", http.StatusTeapot, w.Code)
+	}
+
+	if w.Body.String() != "sub: boom" {
+		t.Errorf("expected body %s, got %s", "sub: boom", w.Body.String())
+	}
+
+	if w.Header().Get("X-Parent") != "" {
+		t.Error("expected parent middleware not to run for mounted router")
+	}
+}
+
+func TestRouterRecover(t *testing.T) {
+	r := New()
+	r.GET("/panic", func(w http.ResponseWriter, r *http.Request) error {
+		panic("something went wrong")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+
+	r = New(WithRecover(false))
+	r.GET("/panic", func(w http.ResponseWriter, r *http.Request) error {
+		panic("something went wrong")
+	})
+
+	defer func() {
+		if p := recover(); p == nil {
+			t.Error("expected panic to propagate when recover is disabled")
+		}
+	}()
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/panic", nil))
+}
+
+func TestRouterBeforeRender(t *testing.T) {
+	r := New(WithBeforeRender(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+	}))
+	r.GET("/ok", Warp(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	r.GET("/fail", func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("fail")
+	})
+
+	for _, path := range []string{"/ok", "/fail"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Header().Get("X-Content-Type-Options") != "nosniff" {
+			t.Errorf("expected hook header on %s", path)
+		}
+	}
+}
+
+// fakeMux is a Mux dispatching on exact "METHOD /path" patterns.
+type fakeMux struct {
+	patterns []string
+	handlers map[string]http.Handler
+}
+
+func (m *fakeMux) Handle(pattern string, handler http.Handler) {
+	m.patterns = append(m.patterns, pattern)
+	m.handlers[pattern] = handler
+}
+
+func (m *fakeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler, ok := m.handlers[r.Method+" "+r.URL.Path]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	handler.ServeHTTP(w, r)
+}
+
+func TestRouterWithMux(t *testing.T) {
+	mux := &fakeMux{handlers: make(map[string]http.Handler)}
+	r := New(WithMux(mux))
+
+	api := r.Group("/api")
+	api.GET("/users", func(w http.ResponseWriter, r *http.Request) error {
+		return errors.New("boom")
+	})
+	r.POST("/login", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("ok"))
+		return err
+	})
+
+	if len(mux.patterns) != 2 || mux.patterns[0] != "GET /api/users" || mux.patterns[1] != "POST /login" {
+		t.Fatalf("unexpected registrations: %v", mux.patterns)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/login", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != "ok" {
+		t.Errorf("expected body %q, got %q", "ok", w.Body.String())
+	}
+
+	// errors still flow through the router's error handler
+	req = httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestRouterFallback(t *testing.T) {
+	r := New()
+	r.GET("/users", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("users"))
+		return err
+	})
+	r.Group("/api").GET("/status", func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("status"))
+		return err
+	})
+	r.Fallback(func(w http.ResponseWriter, r *http.Request) error {
+		_, err := w.Write([]byte("fallback " + r.Method + " " + r.URL.Path))
+		return err
+	})
+
+	tests := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{http.MethodGet, "/users", "users"},
+		{http.MethodGet, "/api/status", "status"},
+		{http.MethodGet, "/legacy/report", "fallback GET /legacy/report"},
+		{http.MethodPost, "/api/status", "fallback POST /api/status"},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+		if w.Code != http.StatusOK || w.Body.String() != tt.body {
+			t.Errorf("%s %s: expected %q, got %d %q", tt.method, tt.target, tt.body, w.Code, w.Body.String())
+		}
+	}
+
+	routes := r.Routes()
+	if last := routes[len(routes)-1]; last.Method != "*" || last.Pattern != "/" {
+		t.Errorf("expected the fallback to be listed as a route, got %+v", last)
+	}
+}
+
+// acceptSchema is a binding.SchemaValidator accepting every document.
+type acceptSchema struct{}
+
+func (acceptSchema) Validate([]byte) ([]binding.SchemaViolation, error) { return nil, nil }
+
+func TestRouterBindingErrorStatus(t *testing.T) {
+	type CreateItem struct {
+		Name string `form:"name" binding:"required"`
+		Qty  int    `form:"qty"`
+	}
+
+	r := New()
+	r.POST("/items", G(func(ctx context.Context, req CreateItem) (string, error) {
+		return req.Name, nil
+	}).String())
+	r.POST("/strict/items", UseBinder(binding.Strict())(G(func(ctx context.Context, req CreateItem) (string, error) {
+		return req.Name, nil
+	}).String()))
+	r.POST("/upstream/items", G(func(ctx context.Context, req CreateItem) (string, error) {
+		var item CreateItem
+		return req.Name, json.Unmarshal([]byte(`{"name":`), &item)
+	}).String())
+	r.POST("/small/items", UseBinder(binding.SchemaBinder{Validator: acceptSchema{}, MaxBodySize: 16})(G(func(ctx context.Context, req CreateItem) (string, error) {
+		return req.Name, nil
+	}).String()))
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"valid", "/items", "application/x-www-form-urlencoded", "name=bolt&qty=2", http.StatusOK},
+		{"malformed field", "/items", "application/x-www-form-urlencoded", "name=bolt&qty=many", http.StatusBadRequest},
+		{"malformed json", "/items", "application/json", `{"name":}`, http.StatusBadRequest},
+		{"json type", "/items", "application/json", `{"name":42}`, http.StatusBadRequest},
+		{"missing required", "/items", "application/x-www-form-urlencoded", "qty=2", http.StatusUnprocessableEntity},
+		{"unsupported media type", "/strict/items", "text/csv", "name,qty\nbolt,2", http.StatusUnsupportedMediaType},
+		{"handler decoding error", "/upstream/items", "application/x-www-form-urlencoded", "name=bolt", http.StatusInternalServerError},
+		{"body too large", "/small/items", "application/json", `{"name":"bolt","qty":2}`, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status code %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader(`{"name":}`))
+	req.Header.Set("Content-Type", "application/json")
+	var item CreateItem
+	var bindErr *BindError
+	if err := ShouldBind(req, &item); !errors.As(err, &bindErr) {
+		t.Errorf("expected ShouldBind to return a *BindError, got %v", err)
+	}
+}
+
+func TestRouterStats(t *testing.T) {
+	r := New()
+	r.GET("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		if r.PathValue("id") == "0" {
+			return errors.New("no such user")
+		}
+		_, err := w.Write([]byte("user"))
+		return err
+	})
+	r.Group("/debug").Stats("/stats")
+
+	for _, id := range []string{"1", "2", "3", "0"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/"+id, nil))
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/stats", nil))
+
+	var stats []RouteStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(stats) != 1 || stats[0].Pattern != "GET /users/{id}" {
+		t.Fatalf("expected stats for GET /users/{id}, got %+v", stats)
+	}
+
+	if stats[0].Requests != 4 || stats[0].Errors != 1 {
+		t.Errorf("expected 4 requests and 1 error, got %d and %d", stats[0].Requests, stats[0].Errors)
+	}
+
+	if stats[0].P50 > stats[0].P90 || stats[0].P90 > stats[0].P99 {
+		t.Errorf("expected ordered percentiles, got %+v", stats[0])
+	}
+}
+
+// brokenPipeWriter is a ResponseWriter whose connection was closed by the client.
+type brokenPipeWriter struct {
+	header      http.Header
+	writeHeader int
+}
+
+func (b *brokenPipeWriter) Header() http.Header { return b.header }
+
+func (b *brokenPipeWriter) WriteHeader(int) { b.writeHeader++ }
+
+func (b *brokenPipeWriter) Write([]byte) (int, error) {
+	return 0, &net.OpError{Op: "write", Net: "tcp", Err: os.NewSyscallError("write", syscall.EPIPE)}
+}
+
+func TestRouterClientGone(t *testing.T) {
+	handled := 0
+	r := New(WithErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+		handled++
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}))
+	r.GET("/report", G(func(ctx context.Context, req httpx.Empty) (map[string]string, error) {
+		return map[string]string{"status": "ok"}, nil
+	}).JSON())
+
+	w := &brokenPipeWriter{header: make(http.Header)}
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/report", nil))
+
+	if handled != 0 {
+		t.Errorf("expected the error handler not to run for a broken pipe, ran %d times", handled)
+	}
+
+	if w.writeHeader != 1 {
+		t.Errorf("expected a single WriteHeader, got %d", w.writeHeader)
+	}
+}
@@ -1,6 +1,10 @@
 package hx
 
-import "github.com/eatmoreapple/hx/internal/serializer"
+import (
+	"net/http"
+
+	"github.com/eatmoreapple/hx/internal/serializer"
+)
 
 // SetJSONSerializer sets the JSON serializer used by the framework.
 // This function allows you to customize the JSON serialization behavior.
@@ -8,3 +12,37 @@ import "github.com/eatmoreapple/hx/internal/serializer"
 func SetJSONSerializer(s serializer.Serializer) {
 	serializer.SetJSONSerializer(s)
 }
+
+// WithSerializer is a middleware that makes the requests it handles use s instead of the
+// serializer set by SetJSONSerializer, both to bind JSON bodies and to render JSON responses.
+// It allows, for example, tenants expecting snake_case keys to be served by a dedicated serializer.
+// Middleware that buffer the response without exposing Unwrap, such as ETag, hide the override
+// from responses rendered below them, so register WithSerializer after them.
+//
+// Example:
+//
+//	r.Group("/legacy").Use(hx.WithSerializer(snakeCaseJSON))
+func WithSerializer(s serializer.Serializer) Middleware {
+	return func(handlerFunc HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			r = r.WithContext(serializer.WithJSONSerializer(r.Context(), s))
+			return handlerFunc(&serializerResponseWriter{ResponseWriter: w, serializer: s}, r)
+		}
+	}
+}
+
+// serializerResponseWriter carries the JSON serializer selected by WithSerializer to JSON responses.
+type serializerResponseWriter struct {
+	http.ResponseWriter
+	serializer serializer.Serializer
+}
+
+// JSONSerializer implements serializer.JSONSerializerCarrier.
+func (s *serializerResponseWriter) JSONSerializer() serializer.Serializer {
+	return s.serializer
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (s *serializerResponseWriter) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
@@ -0,0 +1,136 @@
+package session
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidCookie is returned by codecs when a cookie value was not produced with their key,
+// typically because it has been tampered with.
+var ErrInvalidCookie = errors.New("session: invalid cookie")
+
+// Codec encodes session values into a cookie value and decodes them back.
+// Implementations must reject values they did not produce.
+type Codec interface {
+	// Encode returns the cookie value holding values.
+	Encode(values map[string]string) (string, error)
+
+	// Decode returns the values held by a cookie value, or an error wrapping ErrInvalidCookie.
+	Decode(value string) (map[string]string, error)
+}
+
+// MinSignedKeySize is the minimum length of the key given to SignedCodec.
+const MinSignedKeySize = 32
+
+// SignedCodec returns a Codec that signs the values with HMAC-SHA256 under key.
+// The values are readable by the client but cannot be modified without the key.
+// Panics if key is shorter than MinSignedKeySize bytes, as a short or empty key
+// would let anyone forge a valid cookie. The key should be random.
+func SignedCodec(key []byte) Codec {
+	if len(key) < MinSignedKeySize {
+		panic(fmt.Sprintf("session: signing key must be at least %d bytes, got %d", MinSignedKeySize, len(key)))
+	}
+	return signedCodec{key: key}
+}
+
+// signedCodec is the Codec returned by SignedCodec.
+type signedCodec struct {
+	key []byte
+}
+
+// Encode implements Codec.
+func (c signedCodec) Encode(values map[string]string) (string, error) {
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(c.sign(encoded)), nil
+}
+
+// Decode implements Codec.
+func (c signedCodec) Decode(value string) (map[string]string, error) {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, ErrInvalidCookie
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, c.sign(encoded)) {
+		return nil, ErrInvalidCookie
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCookie
+	}
+	var values map[string]string
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, ErrInvalidCookie
+	}
+	return values, nil
+}
+
+// sign returns the HMAC of the encoded payload.
+func (c signedCodec) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// EncryptedCodec returns a Codec that encrypts and authenticates the values with AES-GCM under key,
+// so the client can neither read nor modify them. The key must be 16, 24 or 32 bytes long,
+// selecting AES-128, AES-192 or AES-256.
+func EncryptedCodec(key []byte) (Codec, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return encryptedCodec{aead: aead}, nil
+}
+
+// encryptedCodec is the Codec returned by EncryptedCodec.
+type encryptedCodec struct {
+	aead cipher.AEAD
+}
+
+// Encode implements Codec.
+func (c encryptedCodec) Encode(values map[string]string) (string, error) {
+	payload, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(c.aead.Seal(nonce, nonce, payload, nil)), nil
+}
+
+// Decode implements Codec.
+func (c encryptedCodec) Decode(value string) (map[string]string, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(sealed) < c.aead.NonceSize() {
+		return nil, ErrInvalidCookie
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	payload, err := c.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidCookie
+	}
+	var values map[string]string
+	if err := json.Unmarshal(payload, &values); err != nil {
+		return nil, ErrInvalidCookie
+	}
+	return values, nil
+}
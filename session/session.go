@@ -0,0 +1,210 @@
+// Package session provides cookie-backed sessions for hx routers.
+// Session values are stored in the cookie itself, signed or encrypted by a Codec,
+// so no server-side storage is needed.
+package session
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/eatmoreapple/hx"
+)
+
+// ErrCookieTooLarge is returned when the encoded session exceeds the size browsers accept for a cookie.
+var ErrCookieTooLarge = errors.New("session: cookie too large")
+
+// maxCookieSize is the largest cookie value browsers are guaranteed to store.
+const maxCookieSize = 4096
+
+// Options configures the Middleware.
+type Options struct {
+	// Key signs the cookie with SignedCodec when Codec is nil.
+	// It must then be at least MinSignedKeySize random bytes.
+	Key []byte
+	// Codec encodes the values into the cookie. Defaults to SignedCodec(Key).
+	Codec Codec
+	// Name of the cookie. Defaults to "session".
+	Name string
+	// Path of the cookie. Defaults to "/".
+	Path string
+	// Domain of the cookie, empty for the host of the request.
+	Domain string
+	// MaxAge is the lifetime of the cookie. Zero makes it a browser session cookie.
+	MaxAge time.Duration
+	// Secure restricts the cookie to HTTPS.
+	Secure bool
+	// SameSite sets the SameSite attribute. Defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+}
+
+// Session holds the values of a request's session. It is safe for concurrent use.
+type Session struct {
+	mu      sync.Mutex
+	values  map[string]string
+	changed bool
+}
+
+// Get returns the value stored under key, and false if there is none.
+func (s *Session) Get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Set stores value under key.
+func (s *Session) Set(key, value string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.values == nil {
+		s.values = make(map[string]string)
+	}
+	s.values[key] = value
+	s.changed = true
+}
+
+// Delete removes the value stored under key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; ok {
+		delete(s.values, key)
+		s.changed = true
+	}
+}
+
+// Clear removes every value. The cookie is deleted when the response is written.
+func (s *Session) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.values) > 0 {
+		s.values = nil
+		s.changed = true
+	}
+}
+
+// sessionKey is the context key under which the Middleware stores the Session.
+type sessionKey struct{}
+
+// From returns the Session stored in ctx by the Middleware, or nil if there is none.
+func From(ctx context.Context) *Session {
+	s, _ := ctx.Value(sessionKey{}).(*Session)
+	return s
+}
+
+// Middleware loads the session from its cookie and stores it in the request context,
+// where handlers retrieve it with From. A missing or invalid cookie, e.g. one that has been
+// tampered with, yields an empty session. If the session is modified, the updated cookie is
+// added to the response before its header is written, or when the handler returns.
+//
+// Example:
+//
+//	r.Use(session.Middleware(session.Options{Key: secret, Secure: true}))
+//
+//	r.POST("/login", func(w http.ResponseWriter, r *http.Request) error {
+//	    session.From(r.Context()).Set("user", userID)
+//	    return nil
+//	})
+//
+// Panics if Codec is nil and Key is shorter than MinSignedKeySize bytes.
+func Middleware(opts Options) hx.Middleware {
+	codec := opts.Codec
+	if codec == nil {
+		codec = SignedCodec(opts.Key)
+	}
+	name := cmp.Or(opts.Name, "session")
+
+	return func(handlerFunc hx.HandlerFunc) hx.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) error {
+			s := &Session{}
+			if cookie, err := r.Cookie(name); err == nil {
+				if values, err := codec.Decode(cookie.Value); err == nil {
+					s.values = values
+				}
+			}
+
+			sw := &sessionWriter{ResponseWriter: w, session: s, codec: codec, name: name, opts: opts}
+			err := handlerFunc(sw, r.WithContext(context.WithValue(r.Context(), sessionKey{}, s)))
+			if flushErr := sw.flush(); err == nil {
+				err = flushErr
+			}
+			return err
+		}
+	}
+}
+
+// sessionWriter adds the session cookie to the response before its header is written.
+type sessionWriter struct {
+	http.ResponseWriter
+	session *Session
+	codec   Codec
+	name    string
+	opts    Options
+	flushed bool
+	err     error
+}
+
+// WriteHeader adds the session cookie and writes the status code.
+func (w *sessionWriter) WriteHeader(status int) {
+	_ = w.flush()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write adds the session cookie and writes b.
+func (w *sessionWriter) Write(b []byte) (int, error) {
+	_ = w.flush()
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWriter for use with http.ResponseController.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flush adds the session cookie once, if the session changed. Later changes are not sent.
+func (w *sessionWriter) flush() error {
+	if w.flushed {
+		return w.err
+	}
+	w.flushed = true
+
+	w.session.mu.Lock()
+	changed, values := w.session.changed, w.session.values
+	w.session.mu.Unlock()
+	if !changed {
+		return nil
+	}
+
+	cookie := &http.Cookie{
+		Name:     w.name,
+		Path:     cmp.Or(w.opts.Path, "/"),
+		Domain:   w.opts.Domain,
+		Secure:   w.opts.Secure,
+		HttpOnly: true,
+		SameSite: cmp.Or(w.opts.SameSite, http.SameSiteLaxMode),
+	}
+	if len(values) == 0 {
+		cookie.MaxAge = -1
+		http.SetCookie(w.ResponseWriter, cookie)
+		return nil
+	}
+
+	value, err := w.codec.Encode(values)
+	if err == nil && len(value) > maxCookieSize {
+		err = ErrCookieTooLarge
+	}
+	if err != nil {
+		w.err = err
+		return err
+	}
+	cookie.Value = value
+	if w.opts.MaxAge > 0 {
+		cookie.MaxAge = int(w.opts.MaxAge.Seconds())
+	}
+	http.SetCookie(w.ResponseWriter, cookie)
+	return nil
+}
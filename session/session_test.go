@@ -0,0 +1,100 @@
+package session
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/eatmoreapple/hx"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestCodecs(t *testing.T) {
+	encrypted, err := EncryptedCodec(testKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	codecs := map[string]Codec{"signed": SignedCodec(testKey), "encrypted": encrypted}
+	for name, codec := range codecs {
+		value, err := codec.Encode(map[string]string{"user": "42"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+
+		values, err := codec.Decode(value)
+		if err != nil || values["user"] != "42" {
+			t.Errorf("%s: expected user 42, got %v %v", name, values, err)
+		}
+
+		tampered := []byte(value)
+		tampered[len(tampered)/3] ^= 1
+		if _, err := codec.Decode(string(tampered)); !errors.Is(err, ErrInvalidCookie) {
+			t.Errorf("%s: expected ErrInvalidCookie for a tampered cookie, got %v", name, err)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	r := hx.New(hx.WithMiddleware(Middleware(Options{Key: testKey})))
+	r.POST("/login", func(w http.ResponseWriter, r *http.Request) error {
+		From(r.Context()).Set("user", "gopher")
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+	r.GET("/me", func(w http.ResponseWriter, r *http.Request) error {
+		user, ok := From(r.Context()).Get("user")
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return nil
+		}
+		_, err := w.Write([]byte(user))
+		return err
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != "session" || !cookies[0].HttpOnly {
+		t.Fatalf("expected an HttpOnly session cookie, got %v", cookies)
+	}
+
+	me := func(cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.AddCookie(cookie)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w = me(cookies[0])
+	if w.Code != http.StatusOK || w.Body.String() != "gopher" {
+		t.Errorf("expected the session to round-trip, got %d %q", w.Code, w.Body.String())
+	}
+
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("expected an unchanged session not to set a cookie")
+	}
+
+	tampered := *cookies[0]
+	tampered.Value = "e30" + tampered.Value[3:]
+	if w := me(&tampered); w.Code != http.StatusUnauthorized {
+		t.Errorf("expected a tampered cookie to be ignored, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestMiddlewareRequiresKey(t *testing.T) {
+	for name, opts := range map[string]Options{"no key": {}, "short key": {Key: []byte("secret")}} {
+		func() {
+			defer func() {
+				if p := recover(); p == nil {
+					t.Errorf("%s: expected Middleware to panic", name)
+				}
+			}()
+			Middleware(opts)
+		}()
+	}
+}
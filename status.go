@@ -0,0 +1,42 @@
+package hx
+
+import "context"
+
+// statusKey is the context key under which the status holder is stored.
+type statusKey struct{}
+
+// statusHolder records the status code requested by SetStatus.
+type statusHolder struct {
+	code int
+}
+
+// withStatusHolder returns a context carrying a fresh status holder.
+func withStatusHolder(ctx context.Context) context.Context {
+	return context.WithValue(ctx, statusKey{}, &statusHolder{})
+}
+
+// SetStatus records the status code to use for the response of the current request.
+// It lets code deep in the call chain decide the status without threading it through
+// return values. The recorded status is used when the handler's result is rendered by
+// JSON, XML, or String. It has no effect on a context that does not come from a handler.
+//
+// Example:
+//
+//	func createJob(ctx context.Context, req JobRequest) (Job, error) {
+//	    job := enqueue(req)
+//	    hx.SetStatus(ctx, http.StatusAccepted)
+//	    return job, nil
+//	}
+func SetStatus(ctx context.Context, code int) {
+	if holder, ok := ctx.Value(statusKey{}).(*statusHolder); ok {
+		holder.code = code
+	}
+}
+
+// statusFromContext returns the status code recorded by SetStatus, or 0 if none was set.
+func statusFromContext(ctx context.Context) int {
+	if holder, ok := ctx.Value(statusKey{}).(*statusHolder); ok {
+		return holder.code
+	}
+	return 0
+}